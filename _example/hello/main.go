@@ -76,6 +76,6 @@ func main() {
 
 	myFunctionLoop.SetLogger(myLog{})
 
-	// run forever in goroutine (or until the function returns ErrLoopExited)
+	// run forever in goroutine (or until the function returns ErrCloseLoop)
 	myFunctionLoop.RunWait(context.Background())
 }
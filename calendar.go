@@ -0,0 +1,305 @@
+package hardloop
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Calendar is a set of excluded dates like holidays or blackout days.
+type Calendar interface {
+	// Excluded returns true if the given time is in an excluded date.
+	Excluded(t time.Time) bool
+}
+
+type calendarDate struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+// DateCalendar excludes whole days.
+type DateCalendar struct {
+	// Location to check the days, nil uses the location of the checked time.
+	Location *time.Location
+
+	dates map[calendarDate]struct{}
+}
+
+var _ Calendar = &DateCalendar{}
+
+// NewDateCalendar returns a calendar excluding the days of the given times.
+//   - Day is taken in the location of the each time.
+func NewDateCalendar(dates ...time.Time) *DateCalendar {
+	c := &DateCalendar{
+		dates: make(map[calendarDate]struct{}, len(dates)),
+	}
+
+	c.Add(dates...)
+
+	return c
+}
+
+// ParseDateCalendar returns a calendar excluding the given days in "2006-01-02" format.
+func ParseDateCalendar(dates ...string) (*DateCalendar, error) {
+	c := NewDateCalendar()
+
+	for _, v := range dates {
+		t, err := time.Parse(time.DateOnly, strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse date %q: %w", v, err)
+		}
+
+		c.Add(t)
+	}
+
+	return c, nil
+}
+
+// LoadICSCalendar reads an iCalendar (.ics) file and excludes the days of its events.
+func LoadICSCalendar(path string) (*DateCalendar, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	c, err := ParseICSCalendar(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	return c, nil
+}
+
+// ParseICSCalendar reads iCalendar data and excludes the days of its events.
+//   - Each VEVENT excludes the days from DTSTART until DTEND, DTEND is exclusive.
+//   - Recurrence rules are not supported.
+func ParseICSCalendar(r io.Reader) (*DateCalendar, error) {
+	lines, err := unfoldICS(r)
+	if err != nil {
+		return nil, err
+	}
+
+	c := NewDateCalendar()
+
+	var (
+		inEvent    bool
+		start, end time.Time
+		endIsDate  bool
+	)
+
+	for _, line := range lines {
+		name, params, value, ok := splitICSLine(line)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			inEvent = true
+			start, end, endIsDate = time.Time{}, time.Time{}, false
+		case name == "END" && value == "VEVENT":
+			inEvent = false
+
+			if start.IsZero() {
+				return nil, errors.New("event without DTSTART")
+			}
+
+			c.addRange(start, end, endIsDate)
+		case inEvent && name == "DTSTART":
+			if start, _, err = parseICSTime(params, value); err != nil {
+				return nil, err
+			}
+		case inEvent && name == "DTEND":
+			if end, endIsDate, err = parseICSTime(params, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return c, nil
+}
+
+// Add excludes the days of the given times.
+func (c *DateCalendar) Add(dates ...time.Time) {
+	if c.dates == nil {
+		c.dates = make(map[calendarDate]struct{}, len(dates))
+	}
+
+	for _, t := range dates {
+		y, m, d := t.Date()
+		c.dates[calendarDate{year: y, month: m, day: d}] = struct{}{}
+	}
+}
+
+// Excluded returns true if the day of the given time is excluded.
+func (c *DateCalendar) Excluded(t time.Time) bool {
+	if c.Location != nil {
+		t = t.In(c.Location)
+	}
+
+	y, m, d := t.Date()
+	_, ok := c.dates[calendarDate{year: y, month: m, day: d}]
+
+	return ok
+}
+
+// addRange adds the days from start to end, end is exclusive for dates and midnight.
+func (c *DateCalendar) addRange(start, end time.Time, endIsDate bool) {
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	if end.IsZero() {
+		c.Add(day)
+
+		return
+	}
+
+	last := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC)
+	if endIsDate || end.Equal(time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, end.Location())) {
+		last = last.AddDate(0, 0, -1)
+	}
+
+	c.Add(day)
+	for day.Before(last) {
+		day = day.AddDate(0, 0, 1)
+		c.Add(day)
+	}
+}
+
+// unfoldICS returns the logical lines, continuation lines start with a space or tab.
+func unfoldICS(r io.Reader) ([]string, error) {
+	var lines []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+
+			continue
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines, scanner.Err()
+}
+
+// splitICSLine splits "NAME;PARAM=X:VALUE" content line.
+func splitICSLine(line string) (name string, params map[string]string, value string, ok bool) {
+	i := strings.Index(line, ":")
+	if i < 0 {
+		return "", nil, "", false
+	}
+
+	parts := strings.Split(line[:i], ";")
+	params = make(map[string]string, len(parts)-1)
+	for _, p := range parts[1:] {
+		if k, v, found := strings.Cut(p, "="); found {
+			params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+		}
+	}
+
+	return strings.ToUpper(parts[0]), params, strings.TrimSpace(line[i+1:]), true
+}
+
+// parseICSTime parses DATE and DATE-TIME values and reports if value is a DATE.
+func parseICSTime(params map[string]string, value string) (time.Time, bool, error) {
+	if params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		t, err := time.Parse("20060102", value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("failed to parse date %q: %w", value, err)
+		}
+
+		return t, true, nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("failed to parse date-time %q: %w", value, err)
+		}
+
+		return t, false, nil
+	}
+
+	loc := time.Local
+	if tzid, ok := params["TZID"]; ok {
+		var err error
+		if loc, err = time.LoadLocation(tzid); err != nil {
+			return time.Time{}, false, fmt.Errorf("provided bad location %s: %w", tzid, err)
+		}
+	}
+
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("failed to parse date-time %q: %w", value, err)
+	}
+
+	return t, false, nil
+}
+
+// Exclude returns a schedule skipping the activations in the excluded dates of calendars.
+func Exclude(schedule Schedule, calendars ...Calendar) Schedule { //nolint:ireturn // return interface to support other interfaces
+	if len(calendars) == 0 {
+		return schedule
+	}
+
+	return &excludeSchedule{
+		schedule:  schedule,
+		calendars: calendars,
+	}
+}
+
+type excludeSchedule struct {
+	schedule  Schedule
+	calendars []Calendar
+}
+
+func (s *excludeSchedule) excluded(t time.Time) bool {
+	for _, c := range s.calendars {
+		if c.Excluded(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *excludeSchedule) yearLimit() int {
+	return horizon(s.schedule)
+}
+
+func (s *excludeSchedule) Next(t time.Time) time.Time {
+	limit := t.AddDate(s.yearLimit(), 0, 0)
+
+	for {
+		t = s.schedule.Next(t)
+		if t.IsZero() || t.After(limit) {
+			return time.Time{}
+		}
+
+		if !s.excluded(t) {
+			return t
+		}
+	}
+}
+
+func (s *excludeSchedule) Prev(t time.Time) time.Time {
+	limit := t.AddDate(-s.yearLimit(), 0, 0)
+
+	for {
+		t = s.schedule.Prev(t)
+		if t.IsZero() || t.Before(limit) {
+			return time.Time{}
+		}
+
+		if !s.excluded(t) {
+			return t
+		}
+	}
+}
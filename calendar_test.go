@@ -0,0 +1,140 @@
+package hardloop_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/worldline-go/hardloop"
+)
+
+const testICS = `BEGIN:VCALENDAR
+VERSION:2.0
+BEGIN:VEVENT
+SUMMARY:New Year
+DTSTART;VALUE=DATE:20240101
+DTEND;VALUE=DATE:20240102
+END:VEVENT
+BEGIN:VEVENT
+SUMMARY:Long
+  weekend
+DTSTART;VALUE=DATE:20240329
+DTEND;VALUE=DATE:20240402
+END:VEVENT
+BEGIN:VEVENT
+SUMMARY:Maintenance
+DTSTART;TZID=Europe/Amsterdam:20240506T090000
+DTEND;TZID=Europe/Amsterdam:20240506T120000
+END:VEVENT
+END:VCALENDAR
+`
+
+func TestParseICSCalendar(t *testing.T) {
+	c, err := hardloop.ParseICSCalendar(strings.NewReader(testICS))
+	if err != nil {
+		t.Fatalf("failed to parse calendar: %v", err)
+	}
+
+	tests := []struct {
+		date time.Time
+		want bool
+	}{
+		{date: time.Date(2023, 12, 31, 10, 0, 0, 0, time.UTC), want: false},
+		{date: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), want: true},
+		{date: time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC), want: false},
+		{date: time.Date(2024, 3, 29, 10, 0, 0, 0, time.UTC), want: true},
+		{date: time.Date(2024, 4, 1, 10, 0, 0, 0, time.UTC), want: true},
+		{date: time.Date(2024, 4, 2, 10, 0, 0, 0, time.UTC), want: false},
+		{date: time.Date(2024, 5, 6, 10, 0, 0, 0, time.UTC), want: true},
+		{date: time.Date(2024, 5, 7, 10, 0, 0, 0, time.UTC), want: false},
+	}
+
+	for _, tt := range tests {
+		if got := c.Excluded(tt.date); got != tt.want {
+			t.Errorf("Excluded(%v) = %v, want %v", tt.date, got, tt.want)
+		}
+	}
+}
+
+func TestLoadICSCalendar(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "holidays.ics")
+	if err := os.WriteFile(path, []byte(testICS), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := hardloop.LoadICSCalendar(path)
+	if err != nil {
+		t.Fatalf("failed to load calendar: %v", err)
+	}
+
+	if !c.Excluded(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected 2024-01-01 to be excluded")
+	}
+
+	if _, err := hardloop.LoadICSCalendar(filepath.Join(t.TempDir(), "not-exist.ics")); err == nil {
+		t.Errorf("expected error for missing file")
+	}
+}
+
+func TestExclude(t *testing.T) {
+	holidays, err := hardloop.ParseDateCalendar("2024-01-01", "2024-01-03")
+	if err != nil {
+		t.Fatalf("failed to parse dates: %v", err)
+	}
+
+	s, err := hardloop.ParseStandard("0 7 * * 1-5")
+	if err != nil {
+		t.Fatalf("failed to parse schedule: %v", err)
+	}
+
+	schedule := hardloop.Exclude(s, holidays)
+
+	now := time.Date(2023, 12, 29, 12, 0, 0, 0, time.UTC)
+	next := []time.Time{
+		time.Date(2024, 1, 2, 7, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 4, 7, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 5, 7, 0, 0, 0, time.UTC),
+	}
+
+	for _, want := range next {
+		now = schedule.Next(now)
+		if !now.Equal(want) {
+			t.Fatalf("[next] expected %v, got %v", want, now)
+		}
+	}
+
+	prev := []time.Time{
+		time.Date(2024, 1, 4, 7, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 2, 7, 0, 0, 0, time.UTC),
+		time.Date(2023, 12, 29, 7, 0, 0, 0, time.UTC),
+	}
+
+	for _, want := range prev {
+		now = schedule.Prev(now)
+		if !now.Equal(want) {
+			t.Fatalf("[prev] expected %v, got %v", want, now)
+		}
+	}
+}
+
+func TestScheduleGroup_Exclusions(t *testing.T) {
+	holidays, err := hardloop.ParseDateCalendar("2024-01-02")
+	if err != nil {
+		t.Fatalf("failed to parse dates: %v", err)
+	}
+
+	l, err := hardloop.NewSchedule([]string{"0 7 * * 1-5"}, []string{"0 17 * * 1-5"})
+	if err != nil {
+		t.Fatalf("could not construct receiver type: %v", err)
+	}
+
+	l.Exclusions = []hardloop.Calendar{holidays}
+
+	got := l.NextStartTime(time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC))
+	want := time.Date(2024, 1, 3, 7, 0, 0, 0, time.UTC)
+	if got == nil || !got.Equal(want) {
+		t.Errorf("NextStartTime() got = %v, want %v", got, want)
+	}
+}
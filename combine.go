@@ -0,0 +1,203 @@
+package hardloop
+
+import "time"
+
+// Union returns a schedule activated when any of the schedules is activated.
+func Union(schedules ...Schedule) Schedule { //nolint:ireturn // return interface to support other interfaces
+	return unionSchedule(schedules)
+}
+
+// Intersect returns a schedule activated when all of the schedules are activated at the same time.
+//   - Use a schedule activated in every minute of a range to limit another schedule, e.g. "* 9-16 * * 1-5".
+func Intersect(schedules ...Schedule) Schedule { //nolint:ireturn // return interface to support other interfaces
+	return intersectSchedule(schedules)
+}
+
+// Except returns a schedule activated when the base schedule is activated but none of the excluded ones.
+func Except(base Schedule, excluded ...Schedule) Schedule { //nolint:ireturn // return interface to support other interfaces
+	return &exceptSchedule{
+		base:     base,
+		excluded: excluded,
+	}
+}
+
+// Shift returns a schedule activated offset duration after the given schedule.
+//   - Negative offset activates before the schedule.
+func Shift(schedule Schedule, offset time.Duration) Schedule { //nolint:ireturn // return interface to support other interfaces
+	return &shiftSchedule{
+		schedule: schedule,
+		offset:   offset,
+	}
+}
+
+type unionSchedule []Schedule
+
+func (s unionSchedule) yearLimit() int {
+	return horizon(s...)
+}
+
+func (s unionSchedule) Next(t time.Time) time.Time {
+	return FindNext(s, t)
+}
+
+func (s unionSchedule) Prev(t time.Time) time.Time {
+	return FindPrev(s, t)
+}
+
+type intersectSchedule []Schedule
+
+func (s intersectSchedule) yearLimit() int {
+	return horizon(s...)
+}
+
+func (s intersectSchedule) Next(t time.Time) time.Time {
+	if len(s) == 0 {
+		return time.Time{}
+	}
+
+	limit := t.AddDate(s.yearLimit(), 0, 0)
+
+	candidate := s[0].Next(t)
+	for !candidate.IsZero() && !candidate.After(limit) {
+		matched := true
+
+		for _, schedule := range s {
+			next := nextOrEqual(schedule, candidate)
+			if next.IsZero() {
+				return time.Time{}
+			}
+
+			if next.After(candidate) {
+				candidate = next
+				matched = false
+
+				break
+			}
+		}
+
+		if matched {
+			return candidate
+		}
+	}
+
+	return time.Time{}
+}
+
+func (s intersectSchedule) Prev(t time.Time) time.Time {
+	if len(s) == 0 {
+		return time.Time{}
+	}
+
+	limit := t.AddDate(-s.yearLimit(), 0, 0)
+
+	candidate := s[0].Prev(t)
+	for !candidate.IsZero() && !candidate.Before(limit) {
+		matched := true
+
+		for _, schedule := range s {
+			prev := prevOrEqual(schedule, candidate)
+			if prev.IsZero() {
+				return time.Time{}
+			}
+
+			if prev.Before(candidate) {
+				candidate = prev
+				matched = false
+
+				break
+			}
+		}
+
+		if matched {
+			return candidate
+		}
+	}
+
+	return time.Time{}
+}
+
+type exceptSchedule struct {
+	base     Schedule
+	excluded []Schedule
+}
+
+func (s *exceptSchedule) isExcluded(t time.Time) bool {
+	for _, schedule := range s.excluded {
+		if nextOrEqual(schedule, t).Equal(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *exceptSchedule) yearLimit() int {
+	return horizon(s.base)
+}
+
+func (s *exceptSchedule) Next(t time.Time) time.Time {
+	limit := t.AddDate(s.yearLimit(), 0, 0)
+
+	for {
+		t = s.base.Next(t)
+		if t.IsZero() || t.After(limit) {
+			return time.Time{}
+		}
+
+		if !s.isExcluded(t) {
+			return t
+		}
+	}
+}
+
+func (s *exceptSchedule) Prev(t time.Time) time.Time {
+	limit := t.AddDate(-s.yearLimit(), 0, 0)
+
+	for {
+		t = s.base.Prev(t)
+		if t.IsZero() || t.Before(limit) {
+			return time.Time{}
+		}
+
+		if !s.isExcluded(t) {
+			return t
+		}
+	}
+}
+
+type shiftSchedule struct {
+	schedule Schedule
+	offset   time.Duration
+}
+
+func (s *shiftSchedule) yearLimit() int {
+	return horizon(s.schedule)
+}
+
+func (s *shiftSchedule) Next(t time.Time) time.Time {
+	next := s.schedule.Next(t.Add(-s.offset))
+	if next.IsZero() {
+		return next
+	}
+
+	return next.Add(s.offset)
+}
+
+func (s *shiftSchedule) Prev(t time.Time) time.Time {
+	prev := s.schedule.Prev(t.Add(-s.offset))
+	if prev.IsZero() {
+		return prev
+	}
+
+	return prev.Add(s.offset)
+}
+
+// nextOrEqual returns the first activation at or after t.
+func nextOrEqual(schedule Schedule, t time.Time) time.Time {
+	return schedule.Next(t.Add(-time.Nanosecond))
+}
+
+// prevOrEqual returns the last activation at or before t.
+func prevOrEqual(schedule Schedule, t time.Time) time.Time {
+	return schedule.Prev(t.Add(time.Nanosecond))
+}
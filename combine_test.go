@@ -0,0 +1,114 @@
+package hardloop_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/worldline-go/hardloop"
+)
+
+func mustParse(t *testing.T, spec string) hardloop.Schedule {
+	t.Helper()
+
+	s, err := hardloop.ParseStandard(spec)
+	if err != nil {
+		t.Fatalf("failed to parse schedule %q: %v", spec, err)
+	}
+
+	return s
+}
+
+func TestCombine(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule func(t *testing.T) hardloop.Schedule
+		now      time.Time
+		next     []time.Time
+		prev     []time.Time
+	}{
+		{
+			name: "union",
+			schedule: func(t *testing.T) hardloop.Schedule {
+				return hardloop.Union(mustParse(t, "0 7 * * *"), mustParse(t, "0 9 * * 6"))
+			},
+			// saturday
+			now: time.Date(2024, 1, 6, 8, 0, 0, 0, time.UTC),
+			next: []time.Time{
+				time.Date(2024, 1, 6, 9, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 7, 7, 0, 0, 0, time.UTC),
+			},
+			prev: []time.Time{
+				time.Date(2024, 1, 6, 7, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 5, 7, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "every 15 minutes in business hours except friday afternoon",
+			schedule: func(t *testing.T) hardloop.Schedule {
+				return hardloop.Except(
+					hardloop.Intersect(mustParse(t, "*/15 * * * *"), mustParse(t, "* 9-16 * * 1-5")),
+					mustParse(t, "* 12-16 * * 5"),
+				)
+			},
+			// friday
+			now: time.Date(2024, 1, 5, 11, 40, 0, 0, time.UTC),
+			next: []time.Time{
+				time.Date(2024, 1, 5, 11, 45, 0, 0, time.UTC),
+				time.Date(2024, 1, 8, 9, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 8, 9, 15, 0, 0, time.UTC),
+			},
+			prev: []time.Time{
+				time.Date(2024, 1, 5, 11, 30, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "intersect prev",
+			schedule: func(t *testing.T) hardloop.Schedule {
+				return hardloop.Intersect(mustParse(t, "*/15 * * * *"), mustParse(t, "* 9-16 * * 1-5"))
+			},
+			// monday
+			now: time.Date(2024, 1, 8, 9, 5, 0, 0, time.UTC),
+			prev: []time.Time{
+				time.Date(2024, 1, 8, 9, 0, 0, 0, time.UTC),
+				time.Date(2024, 1, 5, 16, 45, 0, 0, time.UTC),
+				time.Date(2024, 1, 5, 16, 30, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "shift",
+			schedule: func(t *testing.T) hardloop.Schedule {
+				return hardloop.Shift(mustParse(t, "0 2 * * *"), 90*time.Minute)
+			},
+			now: time.Date(2024, 1, 1, 3, 0, 0, 0, time.UTC),
+			next: []time.Time{
+				time.Date(2024, 1, 1, 3, 30, 0, 0, time.UTC),
+				time.Date(2024, 1, 2, 3, 30, 0, 0, time.UTC),
+			},
+			prev: []time.Time{
+				time.Date(2023, 12, 31, 3, 30, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := tt.schedule(t)
+
+			now := tt.now
+			for _, want := range tt.next {
+				now = schedule.Next(now)
+				if !now.Equal(want) {
+					t.Fatalf("[next] expected %v, got %v", want, now)
+				}
+			}
+
+			now = tt.now
+			for _, want := range tt.prev {
+				now = schedule.Prev(now)
+				if !now.Equal(want) {
+					t.Fatalf("[prev] expected %v, got %v", want, now)
+				}
+			}
+		})
+	}
+}
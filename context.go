@@ -0,0 +1,61 @@
+package hardloop
+
+import (
+	"context"
+	"time"
+)
+
+// JobInfo is the metadata of the running function.
+type JobInfo struct {
+	// Name is the name of the loop or the cron job.
+	Name string
+	// ScheduledTime is the start time of the run from the schedule, before splay and jitter.
+	//   - Restarts and retries of the loop use the time they are triggered in.
+	ScheduledTime time.Time
+	// Attempt is the number of the run, starts from 1.
+	//   - Increases with the retries asked by RetryAfter, other runs start from 1.
+	//   - Loop counts the retries in the same window, cron job counts the retries of the scheduled time.
+	Attempt int
+	// WindowStart is the start of the window the loop is running in, zero for cron jobs.
+	WindowStart time.Time
+	// WindowStop is the planned stop time of the window, zero if it never stops and for cron jobs.
+	WindowStop time.Time
+}
+
+// Remaining returns the duration until the planned stop time, zero if there is no stop time.
+func (j JobInfo) Remaining(now time.Time) time.Duration {
+	if j.WindowStop.IsZero() {
+		return 0
+	}
+
+	return j.WindowStop.Sub(now)
+}
+
+type jobInfoKey struct{}
+
+// JobFromContext returns the metadata of the running function from its context.
+//   - Returns false if the context is not created by Loop or cron job.
+func JobFromContext(ctx context.Context) (JobInfo, bool) {
+	info, ok := ctx.Value(jobInfoKey{}).(JobInfo)
+
+	return info, ok
+}
+
+func withJobInfo(ctx context.Context, info JobInfo) context.Context {
+	return context.WithValue(ctx, jobInfoKey{}, info)
+}
+
+type stopNoticeKey struct{}
+
+// StopNotice returns a channel closed before the loop stops the function, set with WithStopNotice.
+//   - Channel is also closed in the stop time, stop grace period starts after that.
+//   - Returns nil channel, blocking forever, if the context is not created by Loop.
+func StopNotice(ctx context.Context) <-chan struct{} {
+	notice, _ := ctx.Value(stopNoticeKey{}).(chan struct{})
+
+	return notice
+}
+
+func withStopNotice(ctx context.Context, notice chan struct{}) context.Context {
+	return context.WithValue(ctx, stopNoticeKey{}, notice)
+}
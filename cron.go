@@ -1,12 +1,16 @@
 package hardloop
 
 import (
+	"fmt"
+	"math/bits"
+	"strings"
 	"time"
 
 	"github.com/robfig/cron/v3"
 )
 
 // YearLimit is the maximum number of years to search for a matching time.
+//   - Used by the schedules without their own YearLimit, set Parser.YearLimit to not affect other schedules.
 var YearLimit = 5
 
 type Schedule interface {
@@ -22,6 +26,16 @@ type DelaySchedule interface {
 
 type SpecSchedule struct {
 	ConstantDelaySchedule time.Duration
+	// Anchor is the reference time of the ConstantDelaySchedule, activations are anchor + n*delay.
+	//   - Zero value anchors to the Unix epoch.
+	Anchor time.Time
+	// DSTPolicy is the handling of times skipped or repeated by daylight saving changes.
+	DSTPolicy DSTPolicy
+	// DaySemantics is the combination of day of month and day of week when both of them are restricted.
+	DaySemantics DaySemantics
+	// YearLimit is the maximum number of years to search for a matching time.
+	//   - Zero value uses the global YearLimit.
+	YearLimit int
 
 	*cron.SpecSchedule
 }
@@ -37,17 +51,29 @@ const (
 // time.  If no time can be found to satisfy the schedule, return the zero time.
 func (s *SpecSchedule) Next(t time.Time) time.Time {
 	if s.ConstantDelaySchedule != 0 {
-		return t.Add(s.ConstantDelaySchedule - time.Duration(t.Nanosecond())*time.Nanosecond)
+		anchor := s.anchor()
+		n := floorDiv(t.Sub(anchor), s.ConstantDelaySchedule) + 1
+
+		return anchor.Add(time.Duration(n) * s.ConstantDelaySchedule).In(t.Location())
+	}
+
+	next := s.next(t)
+	if s.DSTPolicy == (DSTPolicy{}) {
+		return next
 	}
 
+	return s.nextDST(t, next)
+}
+
+func (s *SpecSchedule) next(t time.Time) time.Time {
 	// General approach
 	//
 	// For Month, Day, Hour, Minute, Second:
 	// Check if the time value matches.  If yes, continue to the next field.
-	// If the field doesn't match the schedule, then increment the field until it matches.
-	// While incrementing the field, a wrap-around brings it back to the beginning
-	// of the field list (since it is necessary to re-verify previous field
-	// values)
+	// If the field doesn't match the schedule, then jump to the next candidate
+	// found in the bits of the field, resetting the lower fields.
+	// After every jump all fields are checked again from the month, since the
+	// jump can change upper fields (e.g. a DST transition in the day).
 
 	// Convert the given time into the schedule's timezone, if one is specified.
 	// Save the original timezone so we can convert back after we find a time.
@@ -65,113 +91,95 @@ func (s *SpecSchedule) Next(t time.Time) time.Time {
 	// Start at the earliest possible time (the upcoming second).
 	t = t.Add(1*time.Second - time.Duration(t.Nanosecond())*time.Nanosecond)
 
-	// This flag indicates whether a field has been incremented.
-	added := false
+	// If no time is found within the year limit, return zero.
+	yearLimit := t.Year() + s.yearLimit()
 
-	// If no time is found within five years, return zero.
-	yearLimit := t.Year() + YearLimit
-
-WRAP:
-	if t.Year() > yearLimit {
-		return time.Time{}
-	}
-
-	// Find the first applicable month.
-	// If it's this month, then do nothing.
-	for 1<<uint(t.Month())&s.Month == 0 {
-		// If we have to add a month, reset the other parts to 0.
-		if !added {
-			added = true
-			// Otherwise, set the date at the beginning (since the current time is irrelevant).
-			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
-		}
-		t = t.AddDate(0, 1, 0)
+	for t.Year() <= yearLimit {
+		if 1<<uint(t.Month())&s.Month == 0 {
+			// Jump to the first day of the next applicable month.
+			if month := nextBit(s.Month, int(t.Month())+1, 12); month > 0 { //nolint:gomnd // months in a year
+				t = dayStart(t.Year(), time.Month(month), 1, loc)
+			} else {
+				t = dayStart(t.Year()+1, time.January, 1, loc)
+			}
 
-		// Wrapped around.
-		if t.Month() == time.January {
-			goto WRAP
+			continue
 		}
-	}
 
-	// Now get a day in that month.
-	//
-	// NOTE: This causes issues for daylight savings regimes where midnight does
-	// not exist.  For example: Sao Paulo has DST that transforms midnight on
-	// 11/3 into 1am. Handle that by noticing when the Hour ends up != 0.
-	for !dayMatches(s, t) {
-		if !added {
-			added = true
-			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
-		}
-		t = t.AddDate(0, 0, 1)
-		// Notice if the hour is no longer midnight due to DST.
-		// Add an hour if it's 23, subtract an hour if it's 1.
-		if t.Hour() != 0 {
-			if t.Hour() > 12 {
-				t = t.Add(time.Duration(24-t.Hour()) * time.Hour)
+		if !dayMatches(s, t) {
+			// Jump to the next applicable day in the month.
+			if day := nextBit(s.monthDays(t.Year(), t.Month()), t.Day()+1, 31); day > 0 { //nolint:gomnd // days in a month
+				t = dayStart(t.Year(), t.Month(), day, loc)
 			} else {
-				t = t.Add(time.Duration(-t.Hour()) * time.Hour)
+				t = dayStart(t.Year(), t.Month()+1, 1, loc)
 			}
-		}
 
-		if t.Day() == 1 {
-			goto WRAP
+			continue
 		}
-	}
 
-	for 1<<uint(t.Hour())&s.Hour == 0 {
-		if !added {
-			added = true
-			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc)
-		}
-		t = t.Add(1 * time.Hour)
+		if 1<<uint(t.Hour())&s.Hour == 0 {
+			if nextBit(s.Hour, t.Hour()+1, 23) < 0 { //nolint:gomnd // hours in a day
+				t = dayStart(t.Year(), t.Month(), t.Day()+1, loc)
+			} else {
+				// Hours are stepped in absolute time to not miss the hours after a DST gap.
+				t = t.Add(time.Hour - time.Duration(t.Minute())*time.Minute - time.Duration(t.Second())*time.Second)
+			}
 
-		if t.Hour() == 0 {
-			goto WRAP
+			continue
 		}
-	}
 
-	for 1<<uint(t.Minute())&s.Minute == 0 {
-		if !added {
-			added = true
-			t = t.Truncate(time.Minute)
-		}
-		t = t.Add(1 * time.Minute)
+		if 1<<uint(t.Minute())&s.Minute == 0 {
+			if minute := nextBit(s.Minute, t.Minute()+1, 59); minute < 0 { //nolint:gomnd // minutes in an hour
+				t = t.Add(time.Hour - time.Duration(t.Minute())*time.Minute - time.Duration(t.Second())*time.Second)
+			} else {
+				t = t.Add(time.Duration(minute-t.Minute())*time.Minute - time.Duration(t.Second())*time.Second)
+			}
 
-		if t.Minute() == 0 {
-			goto WRAP
+			continue
 		}
-	}
 
-	for 1<<uint(t.Second())&s.Second == 0 {
-		if !added {
-			added = true
-			t = t.Truncate(time.Second)
-		}
-		t = t.Add(1 * time.Second)
+		if 1<<uint(t.Second())&s.Second == 0 {
+			if second := nextBit(s.Second, t.Second()+1, 59); second < 0 { //nolint:gomnd // seconds in a minute
+				t = t.Add(time.Minute - time.Duration(t.Second())*time.Second)
+			} else {
+				t = t.Add(time.Duration(second-t.Second()) * time.Second)
+			}
 
-		if t.Second() == 0 {
-			goto WRAP
+			continue
 		}
+
+		return t.In(origLocation)
 	}
 
-	return t.In(origLocation)
+	return time.Time{}
 }
 
 // Prev returns the prev time this schedule is activated, less than the given
 // time.  If no time can be found to satisfy the schedule, return the zero time.
 func (s *SpecSchedule) Prev(t time.Time) time.Time {
 	if s.ConstantDelaySchedule != 0 {
-		return t.Add(-1*s.ConstantDelaySchedule + time.Duration(t.Nanosecond())*time.Nanosecond)
+		anchor := s.anchor()
+		n := ceilDiv(t.Sub(anchor), s.ConstantDelaySchedule) - 1
+
+		return anchor.Add(time.Duration(n) * s.ConstantDelaySchedule).In(t.Location())
 	}
+
+	prev := s.prev(t)
+	if s.DSTPolicy == (DSTPolicy{}) {
+		return prev
+	}
+
+	return s.prevDST(t, prev)
+}
+
+func (s *SpecSchedule) prev(t time.Time) time.Time {
 	// General approach
 	//
 	// For Month, Day, Hour, Minute, Second:
 	// Check if the time value matches.  If yes, continue to the next field.
-	// If the field doesn't match the schedule, then decriment the field until it matches.
-	// While decrementing the field, a wrap-around brings it back to the beginning
-	// of the field list (since it is necessary to re-verify previous field
-	// values)
+	// If the field doesn't match the schedule, then jump to the last second of
+	// the previous candidate found in the bits of the field.
+	// After every jump all fields are checked again from the month.
 
 	// Convert the given time into the schedule's timezone, if one is specified.
 	// Save the original timezone so we can convert back after we find a time.
@@ -186,125 +194,187 @@ func (s *SpecSchedule) Prev(t time.Time) time.Time {
 		t = t.In(s.Location)
 	}
 
-	// Start at the earliest possible time (the upcoming second).
-	t = t.Add(-1*time.Second + time.Duration(t.Nanosecond())*time.Nanosecond)
-
-	// This flag indicates whether a field has been decremented.
-	added := false
+	// Start at the latest possible time (the previous second).
+	t = t.Add(-time.Nanosecond).Truncate(time.Second)
 
-	// If no time is found within five years, return zero.
-	yearLimit := t.Year() - YearLimit
+	// If no time is found within the year limit, return zero.
+	yearLimit := t.Year() - s.yearLimit()
 
-WRAP:
-	if t.Year() < yearLimit {
-		return time.Time{}
-	}
+	for t.Year() >= yearLimit {
+		if 1<<uint(t.Month())&s.Month == 0 {
+			// Jump to the last second of the previous applicable month.
+			if month := prevBit(s.Month, 1, int(t.Month())-1); month > 0 {
+				t = dayStart(t.Year(), time.Month(month)+1, 1, loc).Add(-time.Second)
+			} else {
+				t = dayStart(t.Year(), time.January, 1, loc).Add(-time.Second)
+			}
 
-	// Find the first applicable month.
-	// If it's this month, then do nothing.
-	for 1<<uint(t.Month())&s.Month == 0 {
-		// If we have to add a month, reset the other parts to 0.
-		if !added {
-			added = true
-			// Otherwise, set the date at the beginning (since the current time is irrelevant).
-			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
+			continue
 		}
 
-		t = t.AddDate(0, -1, 0)
+		if !dayMatches(s, t) {
+			// Jump to the last second of the previous applicable day in the month.
+			if day := prevBit(s.monthDays(t.Year(), t.Month()), 1, t.Day()-1); day > 0 {
+				t = dayStart(t.Year(), t.Month(), day+1, loc).Add(-time.Second)
+			} else {
+				t = dayStart(t.Year(), t.Month(), 1, loc).Add(-time.Second)
+			}
+
+			continue
+		}
 
-		if 1<<uint(t.Month())&s.Month != 0 && t.Day() == 1 {
-			year := t.Year()
-			mount := t.Month()
-			if t.Month() == time.December {
-				year++
-				mount = time.January
+		if 1<<uint(t.Hour())&s.Hour == 0 {
+			if prevBit(s.Hour, 0, t.Hour()-1) < 0 {
+				t = dayStart(t.Year(), t.Month(), t.Day(), loc).Add(-time.Second)
 			} else {
-				mount++
+				// Hours are stepped in absolute time to not miss the hours before a DST gap.
+				t = t.Add(-time.Duration(t.Minute())*time.Minute - time.Duration(t.Second()+1)*time.Second)
 			}
 
-			days := time.Date(year, mount, 0, 0, 0, 0, 0, loc).Day()
+			continue
+		}
 
-			t = t.AddDate(0, 0, days-1)
+		if 1<<uint(t.Minute())&s.Minute == 0 {
+			if minute := prevBit(s.Minute, 0, t.Minute()-1); minute < 0 {
+				t = t.Add(-time.Duration(t.Minute())*time.Minute - time.Duration(t.Second()+1)*time.Second)
+			} else {
+				t = t.Add(-time.Duration(t.Minute()-minute-1)*time.Minute - time.Duration(t.Second()+1)*time.Second)
+			}
+
+			continue
 		}
 
-		// Wrapped around.
-		if t.Month() == time.December {
-			goto WRAP
+		if 1<<uint(t.Second())&s.Second == 0 {
+			if second := prevBit(s.Second, 0, t.Second()-1); second < 0 {
+				t = t.Add(-time.Duration(t.Second()+1) * time.Second)
+			} else {
+				t = t.Add(-time.Duration(t.Second()-second) * time.Second)
+			}
+
+			continue
 		}
+
+		return t.In(origLocation)
 	}
 
-	// Now get a day in that month.
-	for !dayMatches(s, t) {
-		if !added {
-			added = true
-			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
-		}
-		currentDay := t.Day()
-		t = t.AddDate(0, 0, -1)
+	return time.Time{}
+}
 
-		if !dayMatches(s, t) && t.Hour() == 0 {
-			t = t.Add(time.Hour * 23) //nolint:gomnd // 23 is the last hour of the day
-		}
+// yearLimit returns the maximum number of years to search for a matching time.
+func (s *SpecSchedule) yearLimit() int {
+	if s.YearLimit > 0 {
+		return s.YearLimit
+	}
+
+	return YearLimit
+}
 
-		if t.Day()-currentDay != 1 {
-			goto WRAP
+// horizon returns the largest year limit of the schedules, the global YearLimit for other schedules.
+func horizon(schedules ...Schedule) int {
+	limit := 0
+
+	for _, schedule := range schedules {
+		if s, ok := schedule.(interface{ yearLimit() int }); ok && s.yearLimit() > limit {
+			limit = s.yearLimit()
 		}
 	}
 
-	for 1<<uint(t.Hour())&s.Hour == 0 {
-		if !added {
-			added = true
-			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc)
-		}
-		t = t.Add(-1 * time.Hour)
+	if limit == 0 {
+		return YearLimit
+	}
 
-		if 1<<uint(t.Hour())&s.Hour != 0 && t.Minute() == 0 {
-			t = t.Add(time.Minute * 59)
-		}
+	return limit
+}
 
-		if t.Hour() == 0 {
-			goto WRAP
+// monthDays returns the bits of the days in the month matching with the schedule.
+func (s *SpecSchedule) monthDays(year int, month time.Month) uint64 {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	days := first.AddDate(0, 1, -1).Day()
+
+	var mask uint64
+	for day := 1; day <= days; day++ {
+		weekday := (int(first.Weekday()) + day - 1) % 7 //nolint:gomnd // days in a week
+		if dayBitsMatch(s, day, time.Weekday(weekday)) {
+			mask |= 1 << uint(day)
 		}
 	}
 
-	for 1<<uint(t.Minute())&s.Minute == 0 {
-		if !added {
-			added = true
-			t = t.Truncate(time.Minute)
-		}
-		t = t.Add(-1 * time.Minute)
+	return mask
+}
 
-		if 1<<uint(t.Minute())&s.Minute != 0 && t.Second() == 0 {
-			t = t.Add(time.Second * 59)
-		}
+// dayStart returns the first instant of the day in the location.
+//   - Day is normalized like time.Date, day 32 is the first day of the next month.
+//   - time.Date is not enough, midnight can be skipped or repeated by daylight saving.
+func dayStart(year int, month time.Month, day int, loc *time.Location) time.Time {
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
 
-		if t.Minute() == 0 {
-			goto WRAP
-		}
+	t := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+	if t.Day() != date.Day() {
+		// midnight is skipped, the day starts in the transition
+		_, end := t.ZoneBounds()
+
+		return end
 	}
 
-	for 1<<uint(t.Second())&s.Second == 0 {
-		if !added {
-			added = true
-			t = t.Truncate(time.Second)
-		}
-		t = t.Add(-1 * time.Second)
+	if isRepeated(t) {
+		// midnight is repeated, use the first one
+		start, _ := t.ZoneBounds()
+		_, before := start.Add(-time.Nanosecond).Zone()
+		_, after := start.Zone()
 
-		// No need to check under seconds.
+		return t.Add(-time.Duration(before-after) * time.Second)
+	}
 
-		if t.Second() == 0 {
-			goto WRAP
-		}
+	return t
+}
+
+// nextBit returns the first set bit of the mask between from and to, -1 if not exist.
+func nextBit(mask uint64, from, to int) int {
+	if from > to {
+		return -1
+	}
+
+	mask = mask >> uint(from) << uint(from) & (1<<uint(to+1) - 1)
+	if mask == 0 {
+		return -1
 	}
 
-	return t.In(origLocation)
+	return bits.TrailingZeros64(mask)
 }
 
+// prevBit returns the last set bit of the mask between from and to, -1 if not exist.
+func prevBit(mask uint64, from, to int) int {
+	if from > to {
+		return -1
+	}
+
+	mask = mask >> uint(from) << uint(from) & (1<<uint(to+1) - 1)
+	if mask == 0 {
+		return -1
+	}
+
+	return 63 - bits.LeadingZeros64(mask) //nolint:gomnd // last bit of uint64
+}
+
+// DaySemantics is the combination of day of month and day of week when both of them are restricted.
+type DaySemantics uint8
+
+const (
+	// DayAnd matches the days in both of day of month and day of week, hardloop's default.
+	DayAnd DaySemantics = iota
+	// DayOr matches the days in day of month or day of week like standard cron and crontab.guru.
+	DayOr
+)
+
 // edited function of github.com/robfig/cron/v3 to changed day of week and day of month both usage.
 func dayMatches(s *SpecSchedule, t time.Time) bool {
+	return dayBitsMatch(s, t.Day(), t.Weekday())
+}
+
+func dayBitsMatch(s *SpecSchedule, day int, weekday time.Weekday) bool {
 	var (
-		domMatch bool = 1<<uint(t.Day())&s.Dom > 0
-		dowMatch bool = 1<<uint(t.Weekday())&s.Dow > 0
+		domMatch bool = 1<<uint(day)&s.Dom > 0
+		dowMatch bool = 1<<uint(weekday)&s.Dow > 0
 	)
 
 	if s.Dom&starBit > 0 && s.Dow&starBit > 0 {
@@ -312,6 +382,10 @@ func dayMatches(s *SpecSchedule, t time.Time) bool {
 	}
 
 	if !(s.Dom&starBit > 0) && !(s.Dow&starBit > 0) {
+		if s.DaySemantics == DayOr {
+			return domMatch || dowMatch
+		}
+
 		return domMatch && dowMatch
 	}
 
@@ -331,50 +405,74 @@ func dayMatches(s *SpecSchedule, t time.Time) bool {
 // It accepts
 //   - Standard crontab specs, e.g. "* * * * ?"
 //   - Descriptors, e.g. "@midnight", "@every 1h30m"
+//   - Anchored intervals, e.g. "@every 1h from 2024-01-01T00:30Z"
 func ParseStandard(spec string) (*SpecSchedule, error) {
-	specSchedule, err := cron.ParseStandard(spec)
-	if err != nil {
-		return nil, err
-	}
-
-	if schedule, ok := specSchedule.(DelaySchedule); ok {
-		return &SpecSchedule{ConstantDelaySchedule: schedule.GetDelay()}, nil
-	}
-
-	if schedule, ok := specSchedule.(cron.ConstantDelaySchedule); ok {
-		return &SpecSchedule{ConstantDelaySchedule: schedule.Delay}, nil
-	}
-
-	return &SpecSchedule{SpecSchedule: specSchedule.(*cron.SpecSchedule)}, nil //nolint:forcetypeassert // no need to check
+	return Parser{}.parse(spec)
 }
 
 // Parser is default parser for cron to replacing the functions.
 type Parser struct {
 	ParseFn func(standardSpec string) (cron.Schedule, error)
+	// DSTPolicy is set to the parsed schedules.
+	DSTPolicy DSTPolicy
+	// DaySemantics is set to the parsed schedules, use DayOr for specs migrated from system crontabs.
+	DaySemantics DaySemantics
+	// YearLimit is set to the parsed schedules, zero value uses the global YearLimit.
+	YearLimit int
+	// Location is used for the specs without CRON_TZ or TZ prefix, nil uses the local time.
+	Location *time.Location
 }
 
 // Parse returns a new cron schedule for the given spec.
 // It use hardloop.Schedule interface instead of cron.Schedule.
 func (p Parser) Parse(spec string) (cron.Schedule, error) { //nolint:ireturn // return interface to support other interfaces
+	schedule, err := p.parse(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+func (p Parser) parse(spec string) (*SpecSchedule, error) {
 	parseFn := p.ParseFn
 	if parseFn == nil {
 		parseFn = cron.ParseStandard
 	}
 
+	spec, anchor, err := splitAnchor(spec, p.Location)
+	if err != nil {
+		return nil, err
+	}
+
 	specSchedule, err := parseFn(spec)
 	if err != nil {
 		return nil, err
 	}
 
 	if schedule, ok := specSchedule.(DelaySchedule); ok {
-		return &SpecSchedule{ConstantDelaySchedule: schedule.GetDelay()}, nil
+		return &SpecSchedule{ConstantDelaySchedule: schedule.GetDelay(), Anchor: anchor}, nil
 	}
 
 	if schedule, ok := specSchedule.(cron.ConstantDelaySchedule); ok {
-		return &SpecSchedule{ConstantDelaySchedule: schedule.Delay}, nil
+		return &SpecSchedule{ConstantDelaySchedule: schedule.Delay, Anchor: anchor}, nil
+	}
+
+	if !anchor.IsZero() {
+		return nil, fmt.Errorf("anchor is only supported with @every: %s", spec)
+	}
+
+	cronSchedule := specSchedule.(*cron.SpecSchedule) //nolint:forcetypeassert // no need to check
+	if p.Location != nil && !hasLocation(spec) {
+		cronSchedule.Location = p.Location
 	}
 
-	return &SpecSchedule{SpecSchedule: specSchedule.(*cron.SpecSchedule)}, nil //nolint:forcetypeassert // no need to check
+	return &SpecSchedule{
+		SpecSchedule: cronSchedule,
+		DSTPolicy:    p.DSTPolicy,
+		DaySemantics: p.DaySemantics,
+		YearLimit:    p.YearLimit,
+	}, nil
 }
 
 // Parse2 is a helper function for parsing the spec and returning the SpecSchedule.
@@ -388,3 +486,90 @@ func (p Parser) Parse2(spec string) (Schedule, error) { //nolint:ireturn // retu
 }
 
 var _ cron.ScheduleParser = &Parser{}
+
+// anchorLayouts are the accepted layouts for the anchor time of "@every <duration> from <anchor>".
+var anchorLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// splitAnchor removes the " from <anchor>" part of the spec and returns the parsed anchor time.
+//   - Anchors without offset are in the CRON_TZ location of the spec, the default location or local time.
+func splitAnchor(spec string, defaultLocation *time.Location) (string, time.Time, error) {
+	i := strings.Index(spec, " from ")
+	if i < 0 || !strings.Contains(spec[:i], "@every") {
+		return spec, time.Time{}, nil
+	}
+
+	loc, err := specLocation(spec)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	if defaultLocation != nil && !hasLocation(spec) {
+		loc = defaultLocation
+	}
+
+	value := strings.TrimSpace(spec[i+len(" from "):])
+	for _, layout := range anchorLayouts {
+		if anchor, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return spec[:i], anchor, nil
+		}
+	}
+
+	return "", time.Time{}, fmt.Errorf("failed to parse anchor time %q", value)
+}
+
+// specLocation returns the location of the CRON_TZ or TZ prefix of the spec, time.Local if not exist.
+func specLocation(spec string) (*time.Location, error) {
+	if !hasLocation(spec) {
+		return time.Local, nil
+	}
+
+	eq := strings.Index(spec, "=")
+	i := strings.Index(spec, " ")
+	if i < eq {
+		return nil, fmt.Errorf("missing spec after location: %s", spec)
+	}
+
+	loc, err := time.LoadLocation(spec[eq+1 : i])
+	if err != nil {
+		return nil, fmt.Errorf("provided bad location %s: %w", spec[eq+1:i], err)
+	}
+
+	return loc, nil
+}
+
+// hasLocation returns true if the spec has a CRON_TZ or TZ prefix.
+func hasLocation(spec string) bool {
+	return strings.HasPrefix(spec, "TZ=") || strings.HasPrefix(spec, "CRON_TZ=")
+}
+
+func (s *SpecSchedule) anchor() time.Time {
+	if s.Anchor.IsZero() {
+		return time.Unix(0, 0)
+	}
+
+	return s.Anchor
+}
+
+func floorDiv(a, b time.Duration) int64 {
+	q := a / b
+	if a%b != 0 && a < 0 {
+		q--
+	}
+
+	return int64(q)
+}
+
+func ceilDiv(a, b time.Duration) int64 {
+	q := a / b
+	if a%b != 0 && a > 0 {
+		q++
+	}
+
+	return int64(q)
+}
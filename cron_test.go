@@ -39,6 +39,14 @@ func Test_ParseSchedule(t *testing.T) {
 						time.Date(2022, 12, 29, 7, 0, 0, 0, time.UTC),
 					},
 				},
+				{
+					// sub-second time in the activation second
+					timeNow: time.Date(2023, 1, 1, 7, 0, 0, 300_000_000, time.UTC),
+					prev: []time.Time{
+						time.Date(2023, 1, 1, 7, 0, 0, 0, time.UTC),
+						time.Date(2022, 12, 31, 7, 0, 0, 0, time.UTC),
+					},
+				},
 			},
 		},
 		{
@@ -104,6 +112,27 @@ func Test_ParseSchedule(t *testing.T) {
 						time.Date(2022, 12, 23, 17, 0, 0, 0, time.UTC),
 					},
 				},
+				{
+					// previous day matches after stepping from a non matching day
+					timeNow: time.Date(2024, 1, 6, 9, 0, 0, 0, time.UTC),
+					prev: []time.Time{
+						time.Date(2024, 1, 5, 17, 0, 0, 0, time.UTC),
+						time.Date(2024, 1, 4, 17, 0, 0, 0, time.UTC),
+					},
+				},
+			},
+		},
+		{
+			message:  "last day of may",
+			schedule: "0 17 31 5 *",
+			tests: []testTime{
+				{
+					timeNow: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+					prev: []time.Time{
+						time.Date(2023, 5, 31, 17, 0, 0, 0, time.UTC),
+						time.Date(2022, 5, 31, 17, 0, 0, 0, time.UTC),
+					},
+				},
 			},
 		},
 		{
@@ -276,6 +305,66 @@ func Test_ParseSchedule(t *testing.T) {
 				},
 			},
 		},
+		{
+			message:  "every 5 minutes not aligned",
+			schedule: "@every 5m",
+			tests: []testTime{
+				{
+					timeNow: time.Date(2023, time.March, 1, 0, 12, 30, 500, time.UTC),
+					next: []time.Time{
+						time.Date(2023, time.March, 1, 0, 15, 0, 0, time.UTC),
+						time.Date(2023, time.March, 1, 0, 20, 0, 0, time.UTC),
+					},
+					prev: []time.Time{
+						time.Date(2023, time.March, 1, 0, 10, 0, 0, time.UTC),
+						time.Date(2023, time.March, 1, 0, 5, 0, 0, time.UTC),
+					},
+				},
+			},
+		},
+		{
+			message:  "every hour from anchor",
+			schedule: "@every 1h from 2024-01-01T00:30Z",
+			tests: []testTime{
+				{
+					timeNow: time.Date(2024, time.March, 5, 10, 0, 0, 0, time.UTC),
+					next: []time.Time{
+						time.Date(2024, time.March, 5, 10, 30, 0, 0, time.UTC),
+						time.Date(2024, time.March, 5, 11, 30, 0, 0, time.UTC),
+					},
+					prev: []time.Time{
+						time.Date(2024, time.March, 5, 9, 30, 0, 0, time.UTC),
+						time.Date(2024, time.March, 5, 8, 30, 0, 0, time.UTC),
+					},
+				},
+				{
+					// before the anchor
+					timeNow: time.Date(2023, time.December, 31, 23, 45, 0, 0, time.UTC),
+					next: []time.Time{
+						time.Date(2024, time.January, 1, 0, 30, 0, 0, time.UTC),
+					},
+					prev: []time.Time{
+						time.Date(2023, time.December, 31, 23, 30, 0, 0, time.UTC),
+					},
+				},
+			},
+		},
+		{
+			message:  "every 90 minutes from anchor in timezone",
+			schedule: "CRON_TZ=Europe/Amsterdam @every 90m from 2024-01-01T00:00",
+			tests: []testTime{
+				{
+					timeNow: time.Date(2024, time.January, 1, 1, 0, 0, 0, logAMS),
+					next: []time.Time{
+						time.Date(2024, time.January, 1, 1, 30, 0, 0, logAMS),
+						time.Date(2024, time.January, 1, 3, 0, 0, 0, logAMS),
+					},
+					prev: []time.Time{
+						time.Date(2024, time.January, 1, 0, 0, 0, 0, logAMS),
+					},
+				},
+			},
+		},
 	}
 	for i, tt := range tests {
 		t.Run(fmt.Sprintf("parse_%d", i), func(t *testing.T) {
@@ -306,3 +395,170 @@ func Test_ParseSchedule(t *testing.T) {
 		})
 	}
 }
+
+func Test_DaySemantics(t *testing.T) {
+	tests := []struct {
+		message   string
+		schedule  string
+		semantics DaySemantics
+		timeNow   time.Time
+		next      []time.Time
+		prev      []time.Time
+	}{
+		{
+			message:   "and semantics",
+			schedule:  "0 0 1,15 * 3",
+			semantics: DayAnd,
+			timeNow:   time.Date(2023, time.August, 5, 2, 26, 0, 0, time.UTC),
+			next: []time.Time{
+				time.Date(2023, time.November, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2023, time.November, 15, 0, 0, 0, 0, time.UTC),
+			},
+			prev: []time.Time{
+				time.Date(2023, time.March, 15, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			message:   "or semantics",
+			schedule:  "0 0 1,15 * 3",
+			semantics: DayOr,
+			timeNow:   time.Date(2023, time.August, 5, 2, 26, 0, 0, time.UTC),
+			next: []time.Time{
+				time.Date(2023, time.August, 9, 0, 0, 0, 0, time.UTC),
+				time.Date(2023, time.August, 15, 0, 0, 0, 0, time.UTC),
+				time.Date(2023, time.August, 16, 0, 0, 0, 0, time.UTC),
+			},
+			prev: []time.Time{
+				time.Date(2023, time.August, 2, 0, 0, 0, 0, time.UTC),
+				time.Date(2023, time.August, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2023, time.July, 26, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			message:   "or semantics with only day of week",
+			schedule:  "0 0 * * 3",
+			semantics: DayOr,
+			timeNow:   time.Date(2023, time.August, 5, 2, 26, 0, 0, time.UTC),
+			next: []time.Time{
+				time.Date(2023, time.August, 9, 0, 0, 0, 0, time.UTC),
+				time.Date(2023, time.August, 16, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			schedule, err := Parser{DaySemantics: tt.semantics}.Parse2(tt.schedule)
+			if err != nil {
+				t.Fatalf("failed to parse schedule: %v", err)
+			}
+
+			now := tt.timeNow
+			for _, next := range tt.next {
+				now = schedule.Next(now)
+				if !now.Equal(next) {
+					t.Fatalf("[next] %s expected %v, got %v", tt.schedule, next, now)
+				}
+			}
+
+			now = tt.timeNow
+			for _, prev := range tt.prev {
+				now = schedule.Prev(now)
+				if !now.Equal(prev) {
+					t.Fatalf("[prev] %s expected %v, got %v", tt.schedule, prev, now)
+				}
+			}
+		})
+	}
+}
+
+func Test_YearLimit(t *testing.T) {
+	// february 29 on monday, previous 2016 and next 2044
+	spec := "0 0 29 2 1"
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		message   string
+		yearLimit int
+		next      time.Time
+		prev      time.Time
+	}{
+		{
+			message: "global limit",
+		},
+		{
+			message:   "long limit",
+			yearLimit: 30,
+			next:      time.Date(2044, time.February, 29, 0, 0, 0, 0, time.UTC),
+			prev:      time.Date(2016, time.February, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			message:   "short limit",
+			yearLimit: 10,
+			prev:      time.Date(2016, time.February, 29, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			schedule, err := Parser{YearLimit: tt.yearLimit}.Parse2(spec)
+			if err != nil {
+				t.Fatalf("failed to parse schedule: %v", err)
+			}
+
+			if got := schedule.Next(now); !got.Equal(tt.next) {
+				t.Errorf("[next] expected %v, got %v", tt.next, got)
+			}
+
+			if got := schedule.Prev(now); !got.Equal(tt.prev) {
+				t.Errorf("[prev] expected %v, got %v", tt.prev, got)
+			}
+
+			excluded := Exclude(schedule, NewDateCalendar(time.Date(2016, time.February, 29, 0, 0, 0, 0, time.UTC)))
+			if got := excluded.Next(now); !got.Equal(tt.next) {
+				t.Errorf("[exclude next] expected %v, got %v", tt.next, got)
+			}
+		})
+	}
+}
+
+func Test_ParserLocation(t *testing.T) {
+	locAMS, err := time.LoadLocation("Europe/Amsterdam")
+	if err != nil {
+		t.Fatalf("failed to load location: %v", err)
+	}
+
+	now := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		message string
+		spec    string
+		next    time.Time
+	}{
+		{
+			message: "default location",
+			spec:    "0 7 * * *",
+			next:    time.Date(2024, time.January, 2, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			message: "spec location",
+			spec:    "CRON_TZ=UTC 0 7 * * *",
+			next:    time.Date(2024, time.January, 2, 7, 0, 0, 0, time.UTC),
+		},
+		{
+			message: "anchor in default location",
+			spec:    "@every 24h from 2024-01-01T07:00",
+			next:    time.Date(2024, time.January, 2, 6, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			schedule, err := Parser{Location: locAMS}.Parse2(tt.spec)
+			if err != nil {
+				t.Fatalf("failed to parse schedule: %v", err)
+			}
+
+			if got := schedule.Next(now); !got.Equal(tt.next) {
+				t.Errorf("[next] expected %v, got %v", tt.next, got)
+			}
+		})
+	}
+}
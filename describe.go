@@ -0,0 +1,369 @@
+package hardloop
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Describe returns an English description of the spec, e.g. "At 07:00 Monday–Friday (Europe/Istanbul)".
+//   - Day of month and day of week are combined as hardloop matches them, use Parser.Describe for other semantics.
+func Describe(spec string) (string, error) {
+	s, err := ParseStandard(spec)
+	if err != nil {
+		return "", err
+	}
+
+	return describeSchedule(s), nil
+}
+
+// Describe returns an English description of the spec parsed with the parser.
+func (p Parser) Describe(spec string) (string, error) {
+	s, err := p.parse(spec)
+	if err != nil {
+		return "", err
+	}
+
+	return describeSchedule(s), nil
+}
+
+// Describe returns an English description of the schedule group,
+// e.g. "Runs from 07:00 to 17:00 Monday–Friday (Europe/Istanbul)".
+func (l *ScheduleGroup) Describe() string {
+	var description string
+
+	switch {
+	case len(l.WindowSchedules) > 0:
+		windows := make([]string, 0, len(l.WindowSchedules))
+		for _, window := range l.WindowSchedules {
+			windows = append(windows, describeWindow(window.Start, window.Stop))
+		}
+
+		description = strings.Join(windows, "; ")
+	case l.Duration > 0:
+		starts := make([]string, 0, len(l.StartSchedules))
+		for _, schedule := range l.StartSchedules {
+			starts = append(starts, lowerFirst(describeSchedule(schedule)))
+		}
+
+		description = fmt.Sprintf("Runs for %s starting %s", formatDuration(l.Duration), joinList(starts, "; "))
+	case len(l.StartSchedules) == 1 && len(l.StopSchedules) == 1:
+		// starts and stops are not paired, merge only the single start and stop
+		description = describeWindow(l.StartSchedules[0], l.StopSchedules[0])
+	default:
+		description = describeStartStop(l.StartSchedules, l.StopSchedules)
+	}
+
+	if len(l.Exclusions) > 0 {
+		description += " except excluded dates"
+	}
+
+	return description
+}
+
+func describeStartStop(startSchedules, stopSchedules []Schedule) string {
+	starts := make([]string, 0, len(startSchedules))
+	for _, schedule := range startSchedules {
+		starts = append(starts, lowerFirst(describeSchedule(schedule)))
+	}
+
+	stops := make([]string, 0, len(stopSchedules))
+	for _, schedule := range stopSchedules {
+		stops = append(stops, lowerFirst(describeSchedule(schedule)))
+	}
+
+	switch {
+	case len(starts) == 0 && len(stops) == 0:
+		return "Runs always"
+	case len(starts) == 0:
+		return "Runs always, restarts " + joinList(stops, "; ")
+	case len(stops) == 0:
+		return "Starts " + joinList(starts, "; ") + " without stop"
+	default:
+		return "Starts " + joinList(starts, "; ") + "; stops " + joinList(stops, "; ")
+	}
+}
+
+// describeWindow returns "Runs from 07:00 to 17:00 Monday–Friday" if start and stop are in the same days,
+// otherwise describes both of them.
+func describeWindow(start, stop Schedule) string {
+	startParts, okStart := describeSpecParts(start)
+	stopParts, okStop := describeSpecParts(stop)
+
+	if okStart && okStop && startParts.clock != "" && stopParts.clock != "" &&
+		startParts.days == stopParts.days && startParts.months == stopParts.months &&
+		startParts.location == stopParts.location {
+		return "Runs from " + startParts.clock + " to " + stopParts.clock + startParts.suffix()
+	}
+
+	return "Runs from " + strings.TrimPrefix(describeSchedule(start), "At ") +
+		" to " + strings.TrimPrefix(describeSchedule(stop), "At ")
+}
+
+func describeSchedule(schedule Schedule) string {
+	s, ok := schedule.(*SpecSchedule)
+	if !ok {
+		return "Custom schedule"
+	}
+
+	if s.ConstantDelaySchedule != 0 {
+		description := "Every " + formatDuration(s.ConstantDelaySchedule)
+		if !s.Anchor.IsZero() {
+			description += " from " + s.Anchor.Format(time.RFC3339)
+		}
+
+		return description
+	}
+
+	parts, _ := describeSpecParts(s)
+
+	return parts.times + parts.suffix()
+}
+
+type specParts struct {
+	// clock is a single time like "07:00", empty if spec has more times.
+	clock    string
+	times    string
+	days     string
+	months   string
+	location string
+}
+
+func (p specParts) suffix() string {
+	v := " " + p.days + p.months
+	if p.location != "" {
+		v += " (" + p.location + ")"
+	}
+
+	return v
+}
+
+func describeSpecParts(schedule Schedule) (specParts, bool) {
+	s, ok := schedule.(*SpecSchedule)
+	if !ok || s.SpecSchedule == nil {
+		return specParts{}, false
+	}
+
+	parts := specParts{
+		days:   describeDays(s),
+		months: describeMonths(s),
+	}
+
+	if s.Location != nil && s.Location != time.Local {
+		parts.location = s.Location.String()
+	}
+
+	seconds := bitValues(s.Second, 0, 59)
+	minutes := bitValues(s.Minute, 0, 59)
+	hours := bitValues(s.Hour, 0, 23)
+
+	if len(seconds) == 1 && len(minutes)*len(hours) <= 4 { //nolint:gomnd // list up to 4 times
+		clocks := make([]string, 0, len(minutes)*len(hours))
+		for _, h := range hours {
+			for _, m := range minutes {
+				clock := fmt.Sprintf("%02d:%02d", h, m)
+				if seconds[0] != 0 {
+					clock += fmt.Sprintf(":%02d", seconds[0])
+				}
+
+				clocks = append(clocks, clock)
+			}
+		}
+
+		if len(clocks) == 1 {
+			parts.clock = clocks[0]
+		}
+
+		parts.times = "At " + joinList(clocks, ", ")
+
+		return parts, true
+	}
+
+	var times string
+
+	switch {
+	case len(seconds) == 60:
+		times = "Every second"
+	case len(seconds) > 1 || seconds[0] != 0:
+		times = "At " + describeValues(seconds, 0, 59, "second")
+	}
+
+	minutesDesc := describeValues(minutes, 0, 59, "minute")
+	if times == "" {
+		if strings.HasPrefix(minutesDesc, "every") {
+			times = upperFirst(minutesDesc)
+		} else {
+			times = "At " + minutesDesc
+		}
+	} else if len(minutes) != 60 {
+		times += " of " + minutesDesc
+	}
+
+	if len(hours) != 24 { //nolint:gomnd // hours in a day
+		first, last := hours[0], hours[len(hours)-1]
+		if last-first+1 == len(hours) {
+			times += fmt.Sprintf(" between %02d:00 and %02d:59", first, last)
+		} else {
+			times += " past " + describeValues(hours, 0, 23, "hour")
+		}
+	}
+
+	parts.times = times
+
+	return parts, true
+}
+
+var weekdayNames = []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}
+
+func describeDays(s *SpecSchedule) string {
+	doms := bitValues(s.Dom, 1, 31)
+	dows := bitValues(s.Dow, 0, 6)
+
+	domRestricted := s.Dom&starBit == 0 && len(doms) != 31
+	dowRestricted := s.Dow&starBit == 0 && len(dows) != 7
+
+	dowDesc := describeNames(dows, weekdayNames, 0)
+	domDesc := "on day " + describeValues(doms, 1, 31, "") + " of the month"
+
+	if s.DaySemantics == DayOr && s.Dom&starBit == 0 && s.Dow&starBit == 0 {
+		if !domRestricted || !dowRestricted {
+			return "every day"
+		}
+
+		return domDesc + " or on " + dowDesc
+	}
+
+	switch {
+	case domRestricted && dowRestricted:
+		return domDesc + " if it is " + dowDesc
+	case domRestricted:
+		return domDesc
+	case dowRestricted:
+		return dowDesc
+	default:
+		return "every day"
+	}
+}
+
+func describeMonths(s *SpecSchedule) string {
+	months := bitValues(s.Month, 1, 12)
+	if len(months) == 12 { //nolint:gomnd // months in a year
+		return ""
+	}
+
+	names := make([]string, 0, 12) //nolint:gomnd // months in a year
+	for m := time.January; m <= time.December; m++ {
+		names = append(names, m.String())
+	}
+
+	return " in " + describeNames(months, names, 1)
+}
+
+// describeValues returns "every 5 minutes", "minute 0 and 30" or "minute 1–10".
+//   - unit is not added if empty.
+func describeValues(values []int, low, high int, unit string) string {
+	if unit != "" && len(values) > 1 {
+		step := values[1] - values[0]
+		stepped := values[0] == low && values[len(values)-1]+step > high
+		for i := 1; i < len(values) && stepped; i++ {
+			stepped = values[i]-values[i-1] == step
+		}
+
+		if stepped {
+			if step == 1 {
+				return "every " + unit
+			}
+
+			return fmt.Sprintf("every %d %ss", step, unit)
+		}
+	}
+
+	names := make([]string, high+1)
+	for i := low; i <= high; i++ {
+		names[i] = strconv.Itoa(i)
+	}
+
+	v := describeNames(values, names, 0)
+	if unit == "" {
+		return v
+	}
+
+	return unit + " " + v
+}
+
+// describeNames joins the names of values with ranges for 3 or more consecutive values.
+func describeNames(values []int, names []string, offset int) string {
+	var items []string
+
+	for i := 0; i < len(values); {
+		j := i
+		for j+1 < len(values) && values[j+1] == values[j]+1 {
+			j++
+		}
+
+		if j-i >= 2 { //nolint:gomnd // use range for 3 or more values
+			items = append(items, names[values[i]-offset]+"–"+names[values[j]-offset])
+		} else {
+			for k := i; k <= j; k++ {
+				items = append(items, names[values[k]-offset])
+			}
+		}
+
+		i = j + 1
+	}
+
+	return joinList(items, ", ")
+}
+
+// bitValues returns the set values of the bits between low and high.
+func bitValues(bits uint64, low, high int) []int {
+	var values []int
+
+	for i := low; i <= high; i++ {
+		if bits&(1<<uint(i)) != 0 {
+			values = append(values, i)
+		}
+	}
+
+	return values
+}
+
+// joinList returns "a", "a and b" or "a, b and c" with the given separator.
+func joinList(items []string, sep string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+
+	return strings.Join(items[:len(items)-1], sep) + " and " + items[len(items)-1]
+}
+
+// formatDuration returns duration without zero units, "1h30m" instead of "1h30m0s".
+func formatDuration(d time.Duration) string {
+	v := d.String()
+	if strings.HasSuffix(v, "m0s") {
+		v = strings.TrimSuffix(v, "0s")
+	}
+
+	if strings.HasSuffix(v, "h0m") {
+		v = strings.TrimSuffix(v, "0m")
+	}
+
+	return v
+}
+
+func lowerFirst(v string) string {
+	if v == "" {
+		return v
+	}
+
+	return strings.ToLower(v[:1]) + v[1:]
+}
+
+func upperFirst(v string) string {
+	if v == "" {
+		return v
+	}
+
+	return strings.ToUpper(v[:1]) + v[1:]
+}
@@ -0,0 +1,136 @@
+package hardloop_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/worldline-go/hardloop"
+)
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{spec: "0 7 * * *", want: "At 07:00 every day"},
+		{spec: "CRON_TZ=Europe/Istanbul 0 7 * * 1-5", want: "At 07:00 Monday–Friday (Europe/Istanbul)"},
+		{spec: "0 9 * * 6", want: "At 09:00 Saturday"},
+		{spec: "0 0,12 * * 1,3", want: "At 00:00 and 12:00 Monday and Wednesday"},
+		{spec: "*/5 9-16 * * 1-5", want: "Every 5 minutes between 09:00 and 16:59 Monday–Friday"},
+		{spec: "* * * * *", want: "Every minute every day"},
+		{spec: "23 0-20/2 * * *", want: "At minute 23 past hour 0, 2, 4, 6, 8, 10, 12, 14, 16, 18 and 20 every day"},
+		{spec: "0 0 1,15 * 3", want: "At 00:00 on day 1 and 15 of the month if it is Wednesday"},
+		{spec: "0 17 23 5 *", want: "At 17:00 on day 23 of the month in May"},
+		{spec: "0 0 1 */3 *", want: "At 00:00 on day 1 of the month in January, April, July and October"},
+		{spec: "@every 1h30m", want: "Every 1h30m"},
+		{spec: "@every 1h from 2024-01-01T00:30Z", want: "Every 1h from 2024-01-01T00:30:00Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := hardloop.Describe(tt.spec)
+			if err != nil {
+				t.Fatalf("Describe() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("Describe() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleGroup_Describe(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule func() (*hardloop.ScheduleGroup, error)
+		want     string
+	}{
+		{
+			name: "multiple starts and stops",
+			schedule: func() (*hardloop.ScheduleGroup, error) {
+				return hardloop.NewSchedule(
+					[]string{"CRON_TZ=Europe/Istanbul 0 7 * * 1-5", "CRON_TZ=Europe/Istanbul 0 9 * * 6"},
+					[]string{"CRON_TZ=Europe/Istanbul 0 17 * * 1-5", "CRON_TZ=Europe/Istanbul 0 13 * * 6"},
+				)
+			},
+			want: "Starts at 07:00 Monday–Friday (Europe/Istanbul) and at 09:00 Saturday (Europe/Istanbul); " +
+				"stops at 17:00 Monday–Friday (Europe/Istanbul) and at 13:00 Saturday (Europe/Istanbul)",
+		},
+		{
+			name: "start and stop in different order",
+			schedule: func() (*hardloop.ScheduleGroup, error) {
+				return hardloop.NewSchedule(
+					[]string{"0 7 * * 1-5", "0 9 * * 6"},
+					[]string{"0 13 * * 6", "0 17 * * 1-5"},
+				)
+			},
+			want: "Starts at 07:00 Monday–Friday and at 09:00 Saturday; stops at 13:00 Saturday and at 17:00 Monday–Friday",
+		},
+		{
+			name: "single start and stop",
+			schedule: func() (*hardloop.ScheduleGroup, error) {
+				return hardloop.NewSchedule(
+					[]string{"CRON_TZ=Europe/Istanbul 0 7 * * 1-5"},
+					[]string{"CRON_TZ=Europe/Istanbul 0 17 * * 1-5"},
+				)
+			},
+			want: "Runs from 07:00 to 17:00 Monday–Friday (Europe/Istanbul)",
+		},
+		{
+			name: "windows",
+			schedule: func() (*hardloop.ScheduleGroup, error) {
+				return hardloop.NewWindowSchedule([]hardloop.Window{
+					{Start: "0 22 * * 5", Stop: "0 6 * * 1"},
+				})
+			},
+			want: "Runs from 22:00 Friday to 06:00 Monday",
+		},
+		{
+			name: "duration",
+			schedule: func() (*hardloop.ScheduleGroup, error) {
+				return hardloop.NewScheduleDuration([]string{"0 2 * * *"}, 90*time.Minute)
+			},
+			want: "Runs for 1h30m starting at 02:00 every day",
+		},
+		{
+			name: "only start",
+			schedule: func() (*hardloop.ScheduleGroup, error) {
+				return hardloop.NewSchedule([]string{"0 7 * * *"}, nil)
+			},
+			want: "Starts at 07:00 every day without stop",
+		},
+		{
+			name: "only stop",
+			schedule: func() (*hardloop.ScheduleGroup, error) {
+				return hardloop.NewSchedule(nil, []string{"0 7 * * *"})
+			},
+			want: "Runs always, restarts at 07:00 every day",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := tt.schedule()
+			if err != nil {
+				t.Fatalf("could not construct receiver type: %v", err)
+			}
+
+			if got := l.Describe(); got != tt.want {
+				t.Errorf("Describe() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParser_Describe(t *testing.T) {
+	got, err := hardloop.Parser{DaySemantics: hardloop.DayOr}.Describe("0 0 1,15 * 3")
+	if err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+
+	want := "At 00:00 on day 1 and 15 of the month or on Wednesday"
+	if got != want {
+		t.Errorf("Describe() got = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,165 @@
+package hardloop
+
+import "time"
+
+// GapPolicy is the handling of times skipped when clocks are set forward.
+type GapPolicy uint8
+
+const (
+	// GapSkip doesn't activate the skipped times.
+	GapSkip GapPolicy = iota
+	// GapShiftForward activates the skipped times shifted by the gap length, 02:30 runs at 03:30.
+	GapShiftForward
+)
+
+// RepeatPolicy is the handling of times repeated when clocks are set back.
+type RepeatPolicy uint8
+
+const (
+	// RepeatTwice activates the repeated times in both occurrences.
+	RepeatTwice RepeatPolicy = iota
+	// RepeatOnce activates the repeated times only in the first occurrence.
+	RepeatOnce
+)
+
+// DSTPolicy is the handling of daylight saving time changes.
+//   - Zero value skips the nonexistent times and runs twice in the repeated times.
+type DSTPolicy struct {
+	Gap    GapPolicy
+	Repeat RepeatPolicy
+}
+
+// nextDST applies the DST policy to the next time found without policy.
+func (s *SpecSchedule) nextDST(t, next time.Time) time.Time {
+	for s.DSTPolicy.Repeat == RepeatOnce && !next.IsZero() && isRepeated(next.In(s.locationFor(t))) {
+		next = s.next(next)
+	}
+
+	if s.DSTPolicy.Gap == GapShiftForward {
+		to := next
+		if to.IsZero() {
+			to = t.AddDate(s.yearLimit(), 0, 0)
+		}
+
+		if shifted := s.shiftedGapTimes(t, to); len(shifted) > 0 {
+			return shifted[0].In(t.Location())
+		}
+	}
+
+	return next
+}
+
+// prevDST applies the DST policy to the previous time found without policy.
+func (s *SpecSchedule) prevDST(t, prev time.Time) time.Time {
+	for s.DSTPolicy.Repeat == RepeatOnce && !prev.IsZero() && isRepeated(prev.In(s.locationFor(t))) {
+		prev = s.prev(prev)
+	}
+
+	if s.DSTPolicy.Gap == GapShiftForward {
+		from := prev
+		if from.IsZero() {
+			from = t.AddDate(-s.yearLimit(), 0, 0)
+		}
+
+		if shifted := s.shiftedGapTimes(from, t); len(shifted) > 0 {
+			return shifted[len(shifted)-1].In(t.Location())
+		}
+	}
+
+	return prev
+}
+
+// shiftedGapTimes returns the skipped activations between from and to, shifted by the gap length.
+func (s *SpecSchedule) shiftedGapTimes(from, to time.Time) []time.Time {
+	var times []time.Time
+
+	for _, gap := range dstGaps(s.locationFor(from), from, to) {
+		for wall := gap.wall; wall.Before(gap.wall.Add(gap.length)); wall = wall.Add(time.Second) {
+			if !s.matchesWall(wall) {
+				continue
+			}
+
+			shifted := gap.at.Add(wall.Sub(gap.wall))
+			if shifted.After(from) && shifted.Before(to) {
+				times = append(times, shifted)
+			}
+		}
+	}
+
+	return times
+}
+
+// isRepeated returns true if the time is in the second occurrence of a repeated wall clock range.
+func isRepeated(t time.Time) bool {
+	start, _ := t.ZoneBounds()
+	if start.IsZero() {
+		return false
+	}
+
+	_, before := start.Add(-time.Nanosecond).Zone()
+	_, after := start.Zone()
+
+	return before > after && t.Sub(start) < time.Duration(before-after)*time.Second
+}
+
+// dstGap is a skipped wall clock range of a location, like 02:00-03:00 in spring forward.
+type dstGap struct {
+	// at is the transition time.
+	at time.Time
+	// wall is the first skipped wall clock time, fields are in UTC.
+	wall time.Time
+	// length of the skipped range.
+	length time.Duration
+}
+
+// dstGaps returns the skipped wall clock ranges of the location between from and to.
+func dstGaps(loc *time.Location, from, to time.Time) []dstGap {
+	var gaps []dstGap
+
+	for t := from.In(loc); ; {
+		_, end := t.ZoneBounds()
+		if end.IsZero() || end.After(to) {
+			return gaps
+		}
+
+		_, before := end.Add(-time.Nanosecond).Zone()
+		_, after := end.Zone()
+		if after > before {
+			gaps = append(gaps, dstGap{
+				at:     end,
+				wall:   end.UTC().Add(time.Duration(before) * time.Second),
+				length: time.Duration(after-before) * time.Second,
+			})
+		}
+
+		t = end
+	}
+}
+
+// matchesWall returns true if the wall clock time, fields are in UTC, matches with the schedule.
+func (s *SpecSchedule) matchesWall(wall time.Time) bool {
+	return 1<<uint(wall.Month())&s.Month != 0 &&
+		dayMatches(s, wall) &&
+		1<<uint(wall.Hour())&s.Hour != 0 &&
+		1<<uint(wall.Minute())&s.Minute != 0 &&
+		1<<uint(wall.Second())&s.Second != 0
+}
+
+// locationFor returns the location used to find activations from the given time.
+//   - Schedules without a location are local to the given time.
+func (s *SpecSchedule) locationFor(t time.Time) *time.Location {
+	if s.Location == time.Local {
+		return t.Location()
+	}
+
+	return s.Location
+}
+
+// location returns the location of the schedule.
+func (s *SpecSchedule) location() *time.Location {
+	if s.SpecSchedule == nil || s.Location == nil {
+		return time.Local
+	}
+
+	return s.Location
+}
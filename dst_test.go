@@ -0,0 +1,229 @@
+package hardloop_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/worldline-go/hardloop"
+)
+
+func utc(year int, month time.Month, day, hour, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestDSTPolicy(t *testing.T) {
+	var (
+		skipTwice  = hardloop.DSTPolicy{}
+		shiftTwice = hardloop.DSTPolicy{Gap: hardloop.GapShiftForward}
+		skipOnce   = hardloop.DSTPolicy{Repeat: hardloop.RepeatOnce}
+		shiftOnce  = hardloop.DSTPolicy{Gap: hardloop.GapShiftForward, Repeat: hardloop.RepeatOnce}
+	)
+
+	tests := []struct {
+		message string
+		spec    string
+		policy  hardloop.DSTPolicy
+		now     time.Time
+		next    []time.Time
+		prev    []time.Time
+	}{
+		// Europe/Amsterdam, 2024-03-31 02:00 CET -> 03:00 CEST
+		{
+			message: "amsterdam gap skip",
+			spec:    "CRON_TZ=Europe/Amsterdam 30 2 * * *",
+			policy:  skipTwice,
+			now:     utc(2024, 3, 30, 12, 0),
+			next:    []time.Time{utc(2024, 4, 1, 0, 30), utc(2024, 4, 2, 0, 30)},
+			prev:    []time.Time{utc(2024, 3, 30, 1, 30), utc(2024, 3, 29, 1, 30)},
+		},
+		{
+			message: "amsterdam gap shift forward",
+			spec:    "CRON_TZ=Europe/Amsterdam 30 2 * * *",
+			policy:  shiftOnce,
+			now:     utc(2024, 3, 30, 12, 0),
+			next:    []time.Time{utc(2024, 3, 31, 1, 30), utc(2024, 4, 1, 0, 30)},
+		},
+		{
+			message: "amsterdam gap shift forward prev",
+			spec:    "CRON_TZ=Europe/Amsterdam 30 2 * * *",
+			policy:  shiftTwice,
+			now:     utc(2024, 4, 1, 12, 0),
+			prev:    []time.Time{utc(2024, 4, 1, 0, 30), utc(2024, 3, 31, 1, 30), utc(2024, 3, 30, 1, 30)},
+		},
+		{
+			message: "amsterdam every 30 minutes shift forward has no duplicates",
+			spec:    "CRON_TZ=Europe/Amsterdam */30 * * * *",
+			policy:  shiftTwice,
+			now:     utc(2024, 3, 31, 0, 15),
+			next:    []time.Time{utc(2024, 3, 31, 0, 30), utc(2024, 3, 31, 1, 0), utc(2024, 3, 31, 1, 30)},
+		},
+		// Europe/Amsterdam, 2024-10-27 03:00 CEST -> 02:00 CET
+		{
+			message: "amsterdam repeat twice",
+			spec:    "CRON_TZ=Europe/Amsterdam 30 2 * * *",
+			policy:  skipTwice,
+			now:     utc(2024, 10, 26, 12, 0),
+			next:    []time.Time{utc(2024, 10, 27, 0, 30), utc(2024, 10, 27, 1, 30), utc(2024, 10, 28, 1, 30)},
+			prev:    []time.Time{utc(2024, 10, 26, 0, 30)},
+		},
+		{
+			message: "amsterdam repeat once",
+			spec:    "CRON_TZ=Europe/Amsterdam 30 2 * * *",
+			policy:  skipOnce,
+			now:     utc(2024, 10, 26, 12, 0),
+			next:    []time.Time{utc(2024, 10, 27, 0, 30), utc(2024, 10, 28, 1, 30)},
+		},
+		{
+			message: "amsterdam repeat once prev",
+			spec:    "CRON_TZ=Europe/Amsterdam 30 2 * * *",
+			policy:  skipOnce,
+			now:     utc(2024, 10, 28, 12, 0),
+			prev:    []time.Time{utc(2024, 10, 28, 1, 30), utc(2024, 10, 27, 0, 30), utc(2024, 10, 26, 0, 30)},
+		},
+		// America/New_York, 2024-03-10 02:00 EST -> 03:00 EDT and 2024-11-03 02:00 EDT -> 01:00 EST
+		{
+			message: "new york gap skip",
+			spec:    "CRON_TZ=America/New_York 30 2 * * *",
+			policy:  skipOnce,
+			now:     utc(2024, 3, 9, 12, 0),
+			next:    []time.Time{utc(2024, 3, 11, 6, 30)},
+		},
+		{
+			message: "new york gap shift forward",
+			spec:    "CRON_TZ=America/New_York 30 2 * * *",
+			policy:  shiftTwice,
+			now:     utc(2024, 3, 9, 12, 0),
+			next:    []time.Time{utc(2024, 3, 10, 7, 30), utc(2024, 3, 11, 6, 30)},
+		},
+		{
+			message: "new york repeat twice",
+			spec:    "CRON_TZ=America/New_York 30 1 * * *",
+			policy:  shiftTwice,
+			now:     utc(2024, 11, 2, 12, 0),
+			next:    []time.Time{utc(2024, 11, 3, 5, 30), utc(2024, 11, 3, 6, 30), utc(2024, 11, 4, 6, 30)},
+		},
+		{
+			message: "new york repeat once",
+			spec:    "CRON_TZ=America/New_York 30 1 * * *",
+			policy:  shiftOnce,
+			now:     utc(2024, 11, 2, 12, 0),
+			next:    []time.Time{utc(2024, 11, 3, 5, 30), utc(2024, 11, 4, 6, 30)},
+			prev:    []time.Time{utc(2024, 11, 2, 5, 30)},
+		},
+		// Australia/Sydney, 2024-10-06 02:00 AEST -> 03:00 AEDT and 2024-04-07 03:00 AEDT -> 02:00 AEST
+		{
+			message: "sydney gap skip",
+			spec:    "CRON_TZ=Australia/Sydney 30 2 * * *",
+			policy:  skipTwice,
+			now:     utc(2024, 10, 5, 0, 0),
+			next:    []time.Time{utc(2024, 10, 6, 15, 30)},
+		},
+		{
+			message: "sydney gap shift forward",
+			spec:    "CRON_TZ=Australia/Sydney 30 2 * * *",
+			policy:  shiftTwice,
+			now:     utc(2024, 10, 5, 0, 0),
+			next:    []time.Time{utc(2024, 10, 5, 16, 30), utc(2024, 10, 6, 15, 30)},
+		},
+		{
+			message: "sydney repeat once",
+			spec:    "CRON_TZ=Australia/Sydney 30 2 * * *",
+			policy:  skipOnce,
+			now:     utc(2024, 4, 6, 0, 0),
+			next:    []time.Time{utc(2024, 4, 6, 15, 30), utc(2024, 4, 7, 16, 30)},
+		},
+		// Australia/Lord_Howe, 30 minutes change, 2024-10-06 02:00 -> 02:30 and 2024-04-07 02:00 -> 01:30
+		{
+			message: "lord howe gap shift forward",
+			spec:    "CRON_TZ=Australia/Lord_Howe 15 2 * * *",
+			policy:  shiftTwice,
+			now:     utc(2024, 10, 5, 0, 0),
+			next:    []time.Time{utc(2024, 10, 5, 15, 45), utc(2024, 10, 6, 15, 15)},
+		},
+		{
+			message: "lord howe gap skip",
+			spec:    "CRON_TZ=Australia/Lord_Howe 15 2 * * *",
+			policy:  skipTwice,
+			now:     utc(2024, 10, 5, 0, 0),
+			next:    []time.Time{utc(2024, 10, 6, 15, 15)},
+		},
+		{
+			message: "lord howe repeat twice",
+			spec:    "CRON_TZ=Australia/Lord_Howe 45 1 * * *",
+			policy:  skipTwice,
+			now:     utc(2024, 4, 6, 0, 0),
+			next:    []time.Time{utc(2024, 4, 6, 14, 45), utc(2024, 4, 6, 15, 15), utc(2024, 4, 7, 15, 15)},
+		},
+		{
+			message: "lord howe repeat once",
+			spec:    "CRON_TZ=Australia/Lord_Howe 45 1 * * *",
+			policy:  skipOnce,
+			now:     utc(2024, 4, 6, 0, 0),
+			next:    []time.Time{utc(2024, 4, 6, 14, 45), utc(2024, 4, 7, 15, 15)},
+		},
+		// America/Sao_Paulo, midnight change, 2018-11-04 00:00 -> 01:00 and 2018-02-18 00:00 -> 2018-02-17 23:00
+		{
+			message: "sao paulo gap skip",
+			spec:    "CRON_TZ=America/Sao_Paulo 30 0 * * *",
+			policy:  skipTwice,
+			now:     utc(2018, 11, 3, 12, 0),
+			next:    []time.Time{utc(2018, 11, 5, 2, 30)},
+		},
+		{
+			message: "sao paulo gap shift forward",
+			spec:    "CRON_TZ=America/Sao_Paulo 30 0 * * *",
+			policy:  shiftTwice,
+			now:     utc(2018, 11, 3, 12, 0),
+			next:    []time.Time{utc(2018, 11, 4, 3, 30), utc(2018, 11, 5, 2, 30)},
+		},
+		{
+			message: "sao paulo repeat twice",
+			spec:    "CRON_TZ=America/Sao_Paulo 30 23 * * *",
+			policy:  skipTwice,
+			now:     utc(2018, 2, 17, 12, 0),
+			next:    []time.Time{utc(2018, 2, 18, 1, 30), utc(2018, 2, 18, 2, 30), utc(2018, 2, 19, 2, 30)},
+		},
+		{
+			message: "sao paulo repeat once",
+			spec:    "CRON_TZ=America/Sao_Paulo 30 23 * * *",
+			policy:  skipOnce,
+			now:     utc(2018, 2, 17, 12, 0),
+			next:    []time.Time{utc(2018, 2, 18, 1, 30), utc(2018, 2, 19, 2, 30)},
+		},
+		// Asia/Kolkata, no DST
+		{
+			message: "kolkata without DST",
+			spec:    "CRON_TZ=Asia/Kolkata 30 2 * * *",
+			policy:  shiftOnce,
+			now:     utc(2024, 3, 30, 12, 0),
+			next:    []time.Time{utc(2024, 3, 30, 21, 0), utc(2024, 3, 31, 21, 0)},
+			prev:    []time.Time{utc(2024, 3, 29, 21, 0)},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("dst_%d", i), func(t *testing.T) {
+			schedule, err := hardloop.Parser{DSTPolicy: tt.policy}.Parse2(tt.spec)
+			if err != nil {
+				t.Fatalf("failed to parse schedule: %v", err)
+			}
+
+			now := tt.now
+			for _, want := range tt.next {
+				now = schedule.Next(now)
+				if !now.Equal(want) {
+					t.Fatalf("[next] %s expected %v, got %v", tt.message, want, now.UTC())
+				}
+			}
+
+			now = tt.now
+			for _, want := range tt.prev {
+				now = schedule.Prev(now)
+				if !now.Equal(want) {
+					t.Fatalf("[prev] %s expected %v, got %v", tt.message, want, now.UTC())
+				}
+			}
+		})
+	}
+}
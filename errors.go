@@ -0,0 +1,148 @@
+package hardloop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+)
+
+var (
+	// ErrSkip is returned when the function has nothing to do, it is not counted as a failure.
+	ErrSkip = errors.New("skip")
+	// ErrStopUntilNextWindow is returned when the function is done for the current window.
+	//   - Loop doesn't run the function again until the next start time after the window.
+	//   - Cron job runs in the next scheduled time as usual.
+	ErrStopUntilNextWindow = errors.New("stop until next window")
+)
+
+// RetryAfterError requests to run the function again after the delay.
+type RetryAfterError struct {
+	Delay time.Duration
+}
+
+// RetryAfter returns an error to run the function again after the delay.
+//   - Loop retries in the same window, waits the next start time if the delay passes the window.
+//   - Cron job retries before its next scheduled time.
+func RetryAfter(d time.Duration) error {
+	return &RetryAfterError{Delay: d}
+}
+
+func (e *RetryAfterError) Error() string {
+	return fmt.Sprintf("retry after %s", e.Delay)
+}
+
+// PermanentError is a failure that running again doesn't fix.
+type PermanentError struct {
+	Err error
+}
+
+// Permanent wraps the error to not run the function again.
+//   - Loop is closed and cron job is removed.
+//   - Returns nil if err is nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &PermanentError{Err: err}
+}
+
+func (e *PermanentError) Error() string {
+	return "permanent: " + e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// PanicError is a recovered panic of the function.
+//   - It doesn't unwrap the panic value, panic(ErrCloseLoop) is a failure not a result to steer the scheduling.
+type PanicError struct {
+	// Value is the value passed to panic, check it with a type assertion.
+	Value any
+	// Stack is the stack trace of the panicking goroutine.
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// ExitReason is the reason of the loop exit.
+type ExitReason uint8
+
+const (
+	// ExitCanceled is the cancellation of the context given to Run.
+	ExitCanceled ExitReason = iota + 1
+	// ExitStopped is the Stop call.
+	ExitStopped
+	// ExitClosed is the function returning ErrCloseLoop.
+	ExitClosed
+	// ExitPermanent is the function returning a PermanentError.
+	ExitPermanent
+)
+
+func (r ExitReason) String() string {
+	switch r {
+	case ExitCanceled:
+		return "canceled"
+	case ExitStopped:
+		return "stopped"
+	case ExitClosed:
+		return "closed"
+	case ExitPermanent:
+		return "failed permanently"
+	default:
+		return "unknown"
+	}
+}
+
+// ExitError describes why the loop exited.
+type ExitError struct {
+	Reason ExitReason
+	// Err is the error returned by the last run of the function, nil if it didn't run or returned nil.
+	Err error
+}
+
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return "loop " + e.Reason.String()
+	}
+
+	return "loop " + e.Reason.String() + ": " + e.Err.Error()
+}
+
+// Unwrap returns the error of the last run of the function.
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// callRecover calls the function and returns a PanicError if it panics.
+func callRecover(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = &PanicError{Value: v, Stack: debug.Stack()}
+		}
+	}()
+
+	return fn(ctx)
+}
+
+// isPermanent returns true if the error is a PermanentError.
+func isPermanent(err error) bool {
+	var permanentErr *PermanentError
+
+	return errors.As(err, &permanentErr)
+}
+
+// retryAfter returns the delay of the RetryAfterError.
+func retryAfter(err error) (time.Duration, bool) {
+	var retryErr *RetryAfterError
+	if errors.As(err, &retryErr) {
+		return retryErr.Delay, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,109 @@
+package hardloop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestLoop_result(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	tests := []struct {
+		name           string
+		policy         RestartPolicy
+		err            error
+		wantSkipWindow bool
+		wantRetryDelay time.Duration
+	}{
+		{name: "success", policy: RestartAlways, err: nil},
+		{name: "failure", policy: RestartAlways, err: errFailed},
+		{name: "success on failure policy", policy: RestartOnFailure, err: nil, wantSkipWindow: true},
+		{name: "failure on failure policy", policy: RestartOnFailure, err: errFailed},
+		{name: "skip on failure policy", policy: RestartOnFailure, err: ErrSkip, wantSkipWindow: true},
+		{name: "never restart", policy: RestartNever, err: errFailed, wantSkipWindow: true},
+		{name: "stop until next window", policy: RestartAlways, err: fmt.Errorf("done: %w", ErrStopUntilNextWindow), wantSkipWindow: true},
+		{name: "retry after", policy: RestartNever, err: fmt.Errorf("busy: %w", RetryAfter(time.Minute)), wantRetryDelay: time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &Loop{restartPolicy: tt.policy}
+
+			skipWindow, retryDelay := l.result(tt.err)
+			if skipWindow != tt.wantSkipWindow || retryDelay != tt.wantRetryDelay {
+				t.Errorf("result() = %v, %v, want %v, %v", skipWindow, retryDelay, tt.wantSkipWindow, tt.wantRetryDelay)
+			}
+		})
+	}
+}
+
+func TestPermanent(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	err := fmt.Errorf("migration: %w", Permanent(errFailed))
+	if !isPermanent(err) {
+		t.Errorf("isPermanent() = false, want true")
+	}
+
+	if !errors.Is(err, errFailed) {
+		t.Errorf("Permanent() doesn't wrap the error")
+	}
+
+	if isPermanent(errFailed) {
+		t.Errorf("isPermanent() = true for not wrapped error")
+	}
+
+	if Permanent(nil) != nil {
+		t.Errorf("Permanent(nil) should be nil")
+	}
+}
+
+func TestCallRecover(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	err := callRecover(t.Context(), func(ctx context.Context) error {
+		panic(errFailed)
+	})
+
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("callRecover() = %v, want PanicError", err)
+	}
+
+	if len(panicErr.Stack) == 0 {
+		t.Errorf("PanicError.Stack is empty")
+	}
+
+	if panicErr.Value != errFailed { //nolint:errorlint // same error
+		t.Errorf("PanicError.Value = %v, want %v", panicErr.Value, errFailed)
+	}
+
+	// panic is not a result of the function
+	if errors.Is(err, errFailed) {
+		t.Errorf("PanicError unwraps the panic value")
+	}
+
+	if err := callRecover(t.Context(), func(ctx context.Context) error { return errFailed }); err != errFailed { //nolint:errorlint // same error
+		t.Errorf("callRecover() = %v, want %v", err, errFailed)
+	}
+}
+
+func TestExitError(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	err := error(&ExitError{Reason: ExitPermanent, Err: Permanent(errFailed)})
+	if err.Error() != "loop failed permanently: permanent: failed" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+
+	if !errors.Is(err, errFailed) {
+		t.Errorf("ExitError doesn't wrap the error of the function")
+	}
+
+	err = &ExitError{Reason: ExitStopped}
+	if err.Error() != "loop stopped" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+}
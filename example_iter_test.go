@@ -0,0 +1,67 @@
+package hardloop_test
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/worldline-go/hardloop"
+)
+
+func ExampleIterNext() {
+	weekdays, err := hardloop.ParseStandard("0 7 * * 1-5")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	saturday, err := hardloop.ParseStandard("0 9 * * 6")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	schedules := []hardloop.Schedule{weekdays, saturday}
+	now := time.Date(2023, 1, 5, 12, 0, 0, 0, time.UTC)
+
+	fmt.Println("Next 3 times:")
+	for t := range hardloop.IterNext(schedules, now, 3) {
+		fmt.Println(t)
+	}
+
+	fmt.Println("Prev 2 times:")
+	for t := range hardloop.IterPrev(schedules, now, 2) {
+		fmt.Println(t)
+	}
+
+	// Output:
+	// Next 3 times:
+	// 2023-01-06 07:00:00 +0000 UTC
+	// 2023-01-07 09:00:00 +0000 UTC
+	// 2023-01-09 07:00:00 +0000 UTC
+	// Prev 2 times:
+	// 2023-01-05 07:00:00 +0000 UTC
+	// 2023-01-04 07:00:00 +0000 UTC
+}
+
+func ExampleBetween() {
+	s, err := hardloop.ParseStandard("0 */6 * * *")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	lastRun := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	missed := hardloop.Between([]hardloop.Schedule{s}, lastRun, now, 100)
+	fmt.Println("Missed runs:", len(missed))
+
+	for _, t := range missed {
+		fmt.Println(t)
+	}
+
+	// Output:
+	// Missed runs: 4
+	// 2023-01-01 06:00:00 +0000 UTC
+	// 2023-01-01 12:00:00 +0000 UTC
+	// 2023-01-01 18:00:00 +0000 UTC
+	// 2023-01-02 00:00:00 +0000 UTC
+}
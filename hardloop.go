@@ -6,60 +6,213 @@ import (
 	"fmt"
 	"log/slog"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 var (
 	// GapDurationStart to start the function should be at least 1 second bigger than gap duration.
+	//   - Default of the loops, use WithGapDurationStart to set it per loop.
 	GapDurationStart time.Duration = 1 * time.Second //nolint:revive // more readable
-	// GapDurationStop to stop the function.
+	// GapDurationStop is added to the stop time of the function.
+	//   - Default of the loops, use WithGapDurationStop to set it per loop.
 	GapDurationStop time.Duration = 0 //nolint:revive // more readable
 
 	// ErrCloseLoop is returned when the loop should be closed.
-	ErrCloseLoop  = errors.New("close loop")
-	errTimeNotSet = errors.New("timeless schedule")
+	ErrCloseLoop = errors.New("close loop")
+	// ErrStopTimeout is reported when the function doesn't return in the stop timeout after cancellation.
+	ErrStopTimeout = errors.New("function doesn't stop after cancellation")
+	errTimeNotSet  = errors.New("timeless schedule")
 )
 
 type Loop struct {
 	scheduleGroup     *ScheduleGroup
 	isLoopRunning     bool
 	isFunctionRunning bool
-	fn                func(ctx context.Context) error
-	mx                sync.RWMutex
-	cancelFn          context.CancelFunc
-	cancelLoop        context.CancelFunc
-	exited            chan struct{}
-	startDuration     chan *time.Duration
-	stopDuration      chan *time.Duration
-	log               Logger
+	// isFunctionStopping is set after cancellation until the function returns.
+	isFunctionStopping bool
+	// run is the number of the function runs to identify the stopping run.
+	run        uint64
+	fn         func(ctx context.Context) error
+	mx         sync.RWMutex
+	cancelFn   context.CancelFunc
+	cancelLoop context.CancelFunc
+	// loopDone is closed when the loop is cancelled, the loop is stopping until done is closed.
+	loopDone <-chan struct{}
+	// wg counts the goroutines of the running loop, including the function.
+	wg sync.WaitGroup
+	// done is closed when the loop exits, recreated in the next Run.
+	done chan struct{}
+	// exitReason is set with the cancellation of the loop, zero for the parent context.
+	exitReason ExitReason
+	// lastErr is the error returned by the last run of the function.
+	lastErr error
+	// exitErr is the reason of the last exit, nil while running.
+	exitErr       *ExitError
+	exited        chan struct{}
+	startDuration chan *time.Duration
+	stopDuration  chan *time.Duration
+	log           atomic.Pointer[loggerHolder]
+	name          string
+	parser        Parser
+	location      *time.Location
+	restartPolicy RestartPolicy
+	hooks         Hooks
+	recoverPanic  bool
+	middlewares   []Middleware
+	stopNotice    time.Duration
+	stopGrace     time.Duration
+	stopTimeout   time.Duration
+	// closeNotice closes the stop notice channel of the running function.
+	closeNotice func()
+	// graceTimer cancels the function after the stop grace period.
+	graceTimer *time.Timer
+	splay      time.Duration
+	jitter     time.Duration
+	// skipWindow is set when the function should not run again in the current window.
+	skipWindow bool
+	// retryDelay is set when the function should run again after the delay in the current window.
+	retryDelay time.Duration
+	// scheduledTime is the start time from the schedule of the next run, zero for restarts.
+	scheduledTime time.Time
+	// retrying is set when the last run asked for a retry, the next run is the next attempt.
+	retrying bool
+	// attempt is the number of the run retried in attemptWindow.
+	attempt          int
+	attemptWindow    time.Time
+	gapDurationStart time.Duration
+	gapDurationStop  time.Duration
+}
+
+// loggerHolder stores the Logger interface in atomic.Pointer, logger can be nil.
+type loggerHolder struct {
+	Logger
 }
 
 // NewLoop returns a new Loop with the given start and end cron specs and function.
 //   - Standard crontab specs, e.g. "* * * * ?"
 //   - Descriptors, e.g. "@midnight", "@every 1h30m"
-func NewLoop(startSpec, endSpec []string, fn func(ctx context.Context) error) (*Loop, error) {
-	scheduleGroup, err := NewSchedule(startSpec, endSpec)
+func NewLoop(startSpec, endSpec []string, fn func(ctx context.Context) error, opts ...LoopOption) (*Loop, error) {
+	l := newLoop(fn, opts)
+
+	scheduleGroup, err := newSchedule(l.parser, startSpec, endSpec)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Loop{
-		scheduleGroup:     scheduleGroup,
+	l.scheduleGroup = scheduleGroup
+
+	return l, nil
+}
+
+// NewLoopDuration returns a new Loop running the function duration long after each start spec.
+//   - "start at 02:00, run for 90 minutes" is NewLoopDuration([]string{"0 2 * * *"}, 90*time.Minute, fn)
+func NewLoopDuration(startSpec []string, duration time.Duration, fn func(ctx context.Context) error, opts ...LoopOption) (*Loop, error) {
+	l := newLoop(fn, opts)
+
+	scheduleGroup, err := newScheduleDuration(l.parser, startSpec, duration)
+	if err != nil {
+		return nil, err
+	}
+
+	l.scheduleGroup = scheduleGroup
+
+	return l, nil
+}
+
+// NewLoopWindows returns a new Loop running the function in the windows of paired start and stop specs.
+func NewLoopWindows(windows []Window, fn func(ctx context.Context) error, opts ...LoopOption) (*Loop, error) {
+	l := newLoop(fn, opts)
+
+	scheduleGroup, err := newWindowSchedule(l.parser, windows)
+	if err != nil {
+		return nil, err
+	}
+
+	l.scheduleGroup = scheduleGroup
+
+	return l, nil
+}
+
+func newLoop(fn func(ctx context.Context) error, opts []LoopOption) *Loop {
+	l := &Loop{
 		isLoopRunning:     false,
 		isFunctionRunning: false,
 		fn:                fn,
+		done:              make(chan struct{}),
 		exited:            make(chan struct{}, 1),
 		startDuration:     make(chan *time.Duration, 1),
 		stopDuration:      make(chan *time.Duration, 1),
-		log:               slog.Default(),
-	}, nil
+		gapDurationStart:  GapDurationStart,
+		gapDurationStop:   GapDurationStop,
+	}
+
+	l.SetLogger(slog.Default())
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	if l.location != nil {
+		l.parser.Location = l.location
+	}
+
+	return l
+}
+
+// Name returns the name of the loop.
+func (l *Loop) Name() string {
+	return l.name
 }
 
 // SetLogger sets the logger for the loop.
 //   - If not set, it uses the default slog logger.
 //   - Set to nil to disable logging.
+//   - Safe to call while the loop is running.
 func (l *Loop) SetLogger(log Logger) {
-	l.log = log
+	l.log.Store(&loggerHolder{Logger: log})
+}
+
+// logInfo logs the message with the name of the loop.
+func (l *Loop) logInfo(msg string) {
+	log := l.log.Load().Logger
+	if log == nil {
+		return
+	}
+
+	if l.name != "" {
+		log.Info(msg, "loop", l.name)
+
+		return
+	}
+
+	log.Info(msg)
+}
+
+// logError logs the message and error with the name of the loop.
+func (l *Loop) logError(msg string, err error, keysAndValues ...interface{}) {
+	log := l.log.Load().Logger
+	if log == nil {
+		return
+	}
+
+	keysAndValues = append([]interface{}{"error", err}, keysAndValues...)
+	if l.name != "" {
+		keysAndValues = append([]interface{}{"loop", l.name}, keysAndValues...)
+	}
+
+	log.Error(msg, keysAndValues...)
+}
+
+// SetExclusions sets the calendars to skip start times in excluded dates.
+// Not effects immediately!
+func (l *Loop) SetExclusions(calendars ...Calendar) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	scheduleGroup := *l.scheduleGroup
+	scheduleGroup.Exclusions = calendars
+	l.scheduleGroup = &scheduleGroup
 }
 
 // ChangeStartSchedules sets the start cron specs.
@@ -68,7 +221,7 @@ func (l *Loop) ChangeStartSchedules(startSpecs []string) error {
 	startSchedules := make([]Schedule, 0, len(startSpecs))
 
 	for _, spec := range startSpecs {
-		startSchedule, err := ParseStandard(spec)
+		startSchedule, err := l.parser.parse(spec)
 		if err != nil {
 			return err
 		}
@@ -76,7 +229,12 @@ func (l *Loop) ChangeStartSchedules(startSpecs []string) error {
 		startSchedules = append(startSchedules, startSchedule)
 	}
 
-	l.scheduleGroup.StartSchedules = startSchedules
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	scheduleGroup := *l.scheduleGroup
+	scheduleGroup.StartSchedules = startSchedules
+	l.scheduleGroup = &scheduleGroup
 
 	return nil
 }
@@ -87,7 +245,7 @@ func (l *Loop) ChangeStopSchedules(stopSpecs []string) error {
 	stopSchedules := make([]Schedule, 0, len(stopSpecs))
 
 	for _, spec := range stopSpecs {
-		stopSchedule, err := ParseStandard(spec)
+		stopSchedule, err := l.parser.parse(spec)
 		if err != nil {
 			return err
 		}
@@ -95,11 +253,24 @@ func (l *Loop) ChangeStopSchedules(stopSpecs []string) error {
 		stopSchedules = append(stopSchedules, stopSchedule)
 	}
 
-	l.scheduleGroup.StopSchedules = stopSchedules
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	scheduleGroup := *l.scheduleGroup
+	scheduleGroup.StopSchedules = stopSchedules
+	l.scheduleGroup = &scheduleGroup
 
 	return nil
 }
 
+// schedules returns the schedule group, setters replace it to not change the group in use.
+func (l *Loop) schedules() *ScheduleGroup {
+	l.mx.RLock()
+	defer l.mx.RUnlock()
+
+	return l.scheduleGroup
+}
+
 // IsLoopRunning returns true if the loop is running.
 func (l *Loop) IsLoopRunning() bool {
 	l.mx.RLock()
@@ -116,69 +287,190 @@ func (l *Loop) IsFunctionRunning() bool {
 	return l.isFunctionRunning
 }
 
-// RunWait starts the loop and wait to exit with ErrLoopExited.
-func (l *Loop) RunWait(ctx context.Context) {
-	wg := &sync.WaitGroup{}
-	l.Run(ctx, wg)
-	wg.Wait()
+// IsFunctionStopping returns true if the function is cancelled and not returned yet.
+//   - Function is not started again until it returns.
+func (l *Loop) IsFunctionStopping() bool {
+	l.mx.RLock()
+	defer l.mx.RUnlock()
+
+	return l.isFunctionStopping
+}
+
+// RunWait starts the loop and waits to exit, returns the reason as *ExitError.
+//   - Waits the running loop if it is already running.
+func (l *Loop) RunWait(ctx context.Context) error {
+	l.Run(ctx, &sync.WaitGroup{})
+	l.Wait()
+
+	return l.Err()
+}
+
+// Err returns the reason of the last exit as *ExitError.
+//   - Returns nil while the loop is running or before the first run.
+func (l *Loop) Err() error {
+	l.mx.RLock()
+	defer l.mx.RUnlock()
+
+	if l.exitErr == nil {
+		return nil
+	}
+
+	return l.exitErr
+}
+
+// Stop stops the loop and cancels the running function, doesn't wait them to exit.
+//   - Use Wait or Done to wait the exit.
+//   - Stopped loop can be started again with Run, Run waits the exit of the stopping loop.
+func (l *Loop) Stop() {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	if !l.isLoopRunning {
+		return
+	}
+
+	l.closeLoop(ExitStopped)
+}
+
+// closeLoop cancels the loop with the reason, first reason is kept, l.mx should be locked.
+func (l *Loop) closeLoop(reason ExitReason) {
+	if l.exitReason == 0 {
+		l.exitReason = reason
+	}
+
+	l.cancelLoop()
+}
+
+// Wait waits the loop and the running function to exit.
+//   - Returns immediately if the loop is not running after an exit.
+//   - Don't call it in the function, it waits the function to return.
+func (l *Loop) Wait() {
+	<-l.Done()
+}
+
+// Done returns a channel closed when the loop and the running function exit.
+//   - Before the first Run, channel is closed after the first exit.
+func (l *Loop) Done() <-chan struct{} {
+	l.mx.RLock()
+	defer l.mx.RUnlock()
+
+	return l.done
 }
 
 // Run starts the loop.
+//   - wg is done when the loop exits, Wait can be used instead.
+//   - Does nothing if the loop is running.
+//   - Waits the exit if the loop is stopping after Stop or the cancellation of its context, don't call it in the function.
 func (l *Loop) Run(ctx context.Context, wg *sync.WaitGroup) {
-	if l.IsLoopRunning() {
+	l.mx.Lock()
+	if l.isLoopRunning {
+		done, loopDone := l.done, l.loopDone
+		l.mx.Unlock()
+
+		select {
+		case <-loopDone:
+			// run again after the exit
+			<-done
+			l.Run(ctx, wg)
+		default:
+		}
+
 		return
 	}
 
+	l.isLoopRunning = true
+	l.exitReason, l.lastErr, l.exitErr = 0, nil, nil
+
+	// reset the previous run
+	select {
+	case <-l.done:
+		l.done = make(chan struct{})
+	default:
+	}
+
+	l.exited = make(chan struct{}, 1)
+	l.startDuration = make(chan *time.Duration, 1)
+	l.stopDuration = make(chan *time.Duration, 1)
+
 	var ctxLoop context.Context
 	ctxLoop, l.cancelLoop = context.WithCancel(ctx)
+	l.loopDone = ctxLoop.Done()
+	l.mx.Unlock()
 
 	// listen function exit
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-
+	l.goLoop(wg, func() {
 		for {
 			select {
 			case <-ctxLoop.Done():
 				return
 			case <-l.exited:
-				now := time.Now().Add(GapDurationStart)
+				now := time.Now().Add(l.gapDurationStart)
 				// check it can run in now
-				stopTime, _ := l.scheduleGroup.getStopTime(now)
-				if stopTime != nil {
-					l.runFunction(ctxLoop, wg)
+				stopTime, _ := l.schedules().getStopTime(now)
+
+				l.mx.Lock()
+				skipWindow, retryDelay := l.skipWindow, l.retryDelay
+				l.skipWindow, l.retryDelay = false, 0
+				l.mx.Unlock()
+
+				if stopTime != nil && !skipWindow && retryDelay > 0 {
+					// retry should see the window with the gap duration
+					if now.Add(retryDelay).Before(*stopTime) {
+						l.logInfo(fmt.Sprintf("Retry after: [%s]", retryDelay))
+						sendDuration(ctxLoop, l.startDuration, &retryDelay)
+
+						continue
+					}
+
+					// retry passes the window, wait the next one
+					skipWindow = true
+				}
+
+				if stopTime != nil && !skipWindow {
+					l.startInWindow(ctxLoop, wg)
 
 					continue
 				}
 
 				now = time.Now()
 				// check next time to start again
-				startTime, _ := l.scheduleGroup.getStartTime(now)
+				startTime, _ := l.schedules().getStartTime(now)
+				if stopTime != nil {
+					// restart policy doesn't allow to run again in this window, wait the next one
+					startTime, _ = l.schedules().getStartTime(*stopTime)
+					if startTime == nil {
+						startTime = stopTime
+					}
+				}
 				if startTime == nil {
 					// disable next start time
-					if l.log != nil {
-						l.log.Info("Next start time disabled")
-					}
-					l.startDuration <- nil
+					l.logInfo("Next start time disabled")
+					sendDuration(ctxLoop, l.startDuration, nil)
 
 					continue
 				}
 
 				// set next start time
-				if l.log != nil {
-					l.log.Info(fmt.Sprintf("Next start time: [%s]", startTime))
+				scheduledTime := *startTime
+				nextTime := scheduledTime.Add(l.startOffset(scheduledTime))
+
+				l.mx.Lock()
+				l.scheduledTime = scheduledTime
+				l.mx.Unlock()
+
+				if nextTime.Equal(scheduledTime) {
+					l.logInfo(fmt.Sprintf("Next start time: [%s]", nextTime))
+				} else {
+					l.logInfo(fmt.Sprintf("Next start time: [%s] scheduled: [%s]", nextTime, scheduledTime))
 				}
-				duration := startTime.Sub(now)
-				l.startDuration <- &duration
+				duration := nextTime.Sub(now)
+				sendDuration(ctxLoop, l.startDuration, &duration)
 			}
 		}
-	}()
+	})
 
 	// listen start time
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-
+	l.goLoop(wg, func() {
 		var chStartDuration <-chan time.Time
 		var startTimer *time.Timer
 
@@ -214,13 +506,10 @@ func (l *Loop) Run(ctx context.Context, wg *sync.WaitGroup) {
 				l.runFunction(ctxLoop, wg)
 			}
 		}
-	}()
+	})
 
 	// listen stop time
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-
+	l.goLoop(wg, func() {
 		var chStopDuration <-chan time.Time
 		var stopTimer *time.Timer
 
@@ -253,91 +542,350 @@ func (l *Loop) Run(ctx context.Context, wg *sync.WaitGroup) {
 				l.stopFunction()
 			}
 		}
-	}()
+	})
 
 	// first initialize
 	l.initializeTime(ctxLoop, wg)
+
+	// wait the exit to reset the loop
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		<-ctxLoop.Done()
+		l.wg.Wait()
+
+		l.mx.Lock()
+		defer l.mx.Unlock()
+
+		if l.exitReason == 0 {
+			l.exitReason = ExitCanceled
+		}
+
+		// function is already cancelled with the loop
+		if l.graceTimer != nil {
+			l.graceTimer.Stop()
+			l.graceTimer = nil
+		}
+
+		l.isLoopRunning = false
+		l.exitErr = &ExitError{Reason: l.exitReason, Err: l.lastErr}
+		close(l.done)
+	}()
+}
+
+// goLoop runs fn in a goroutine counted in wg and the wait group of the loop.
+func (l *Loop) goLoop(wg *sync.WaitGroup, fn func()) {
+	wg.Add(1)
+	l.wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		defer l.wg.Done()
+
+		fn()
+	}()
+}
+
+// sendDuration sends the duration to the listener, gives up if the loop is closed.
+func sendDuration(ctx context.Context, ch chan<- *time.Duration, d *time.Duration) {
+	select {
+	case ch <- d:
+	case <-ctx.Done():
+	}
+}
+
+// triggerExited triggers the exit listener, doesn't block if there is already a trigger.
+func (l *Loop) triggerExited() {
+	select {
+	case l.exited <- struct{}{}:
+	default:
+	}
 }
 
 func (l *Loop) runFunction(ctx context.Context, wg *sync.WaitGroup) {
 	l.mx.Lock()
-	defer l.mx.Unlock()
 
-	if l.isFunctionRunning {
+	// stopping function triggers exited when it returns
+	if l.isFunctionRunning || l.isFunctionStopping || ctx.Err() != nil {
+		l.mx.Unlock()
+
 		return
 	}
 
 	l.isFunctionRunning = true
+	l.run++
 
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		var ctxInFunc context.Context
-		ctxInFunc, l.cancelFn = context.WithCancel(ctx)
-		err := l.fn(ctxInFunc)
+	now := time.Now().Add(l.gapDurationStart)
+	stopTime, _ := l.scheduleGroup.getStopTime(now)
+	info := l.jobInfo(now, stopTime)
+
+	notice := make(chan struct{})
+	l.closeNotice = sync.OnceFunc(func() { close(notice) })
+
+	var noticeTimer *time.Timer
+	if stopTime != nil && l.stopNotice > 0 {
+		// same duration with the stop timer, checked with the gap duration
+		noticeTimer = time.AfterFunc(stopTime.Sub(now)+l.gapDurationStop-l.stopNotice, l.closeNotice)
+	}
+
+	// set before the function to not race with stopFunction
+	var ctxInFunc context.Context
+	ctxInFunc, l.cancelFn = context.WithCancel(withStopNotice(withJobInfo(ctx, info), notice))
+
+	l.goLoop(wg, func() {
+		if l.hooks.OnStart != nil {
+			l.callHook(func() { l.hooks.OnStart(ctxInFunc) })
+		}
+
+		err := l.callFunction(ctxInFunc)
+		if noticeTimer != nil {
+			noticeTimer.Stop()
+		}
+
+		if l.hooks.OnExit != nil {
+			l.callHook(func() { l.hooks.OnExit(ctxInFunc, err) })
+		}
 
 		// set running to false
 		l.mx.Lock()
 		defer l.mx.Unlock()
 		l.isFunctionRunning = false
+		l.isFunctionStopping = false
+		l.lastErr = err
+		l.skipWindow, l.retryDelay = l.result(err)
+		l.retrying = l.retryDelay > 0
 
 		if errors.Is(err, ErrCloseLoop) {
-			l.cancelLoop()
+			l.closeLoop(ExitClosed)
+
+			return
+		}
+
+		if isPermanent(err) {
+			l.logError("Function failed permanently, closing loop", err)
+			l.closeLoop(ExitPermanent)
 
 			return
 		}
 		// trigger exited
-		l.exited <- struct{}{}
-	}()
+		l.triggerExited()
+	})
+
+	// stop listener locks the loop to stop the function, send after unlocking
+	l.mx.Unlock()
 
 	// set next stop time
-	now := time.Now().Add(GapDurationStart)
-	stopTime, _ := l.scheduleGroup.getStopTime(now)
 	if stopTime == nil {
 		// disable next stop time
-		if l.log != nil {
-			l.log.Info("Next stop time disabled")
-		}
+		l.logInfo("Next stop time disabled")
 
-		l.stopDuration <- nil
+		sendDuration(ctx, l.stopDuration, nil)
 
 		return
 	}
 
-	if l.log != nil {
-		l.log.Info(fmt.Sprintf("Next stop time: [%s]", stopTime))
+	l.logInfo(fmt.Sprintf("Next stop time: [%s]", stopTime))
+
+	stopDuration := stopTime.Sub(now) + l.gapDurationStop
+	sendDuration(ctx, l.stopDuration, &stopDuration)
+}
+
+// jobInfo returns the metadata of the run starting now, l.mx should be locked.
+func (l *Loop) jobInfo(now time.Time, stopTime *time.Time) JobInfo {
+	info := JobInfo{
+		Name:          l.name,
+		ScheduledTime: l.scheduledTime,
+	}
+
+	l.scheduledTime = time.Time{}
+	if info.ScheduledTime.IsZero() {
+		info.ScheduledTime = time.Now()
+	}
+
+	if stopTime != nil {
+		info.WindowStart = l.scheduleGroup.windowStart(now)
+		info.WindowStop = *stopTime
+	}
+
+	if l.retrying && info.WindowStart.Equal(l.attemptWindow) {
+		l.attempt++
+	} else {
+		l.attempt, l.attemptWindow = 1, info.WindowStart
 	}
 
-	stopDuration := stopTime.Sub(now)
-	l.stopDuration <- &stopDuration
+	info.Attempt = l.attempt
+
+	return info
+}
+
+// startOffset returns the splay and jitter delay of the start time, kept in the window of the start.
+//   - Delayed start should see the window, otherwise the function runs without a stop time.
+func (l *Loop) startOffset(startTime time.Time) time.Duration {
+	offset := splayOffset(l.name, l.splay) + jitterOffset(l.jitter)
+	if offset == 0 {
+		return 0
+	}
+
+	stopTime, _ := l.schedules().getStopTime(startTime.Add(l.gapDurationStart))
+	if stopTime == nil {
+		return offset
+	}
+
+	// function starts if the stop time is after the start time with the gap duration
+	window := stopTime.Sub(startTime) - l.gapDurationStart
+	if window <= 0 {
+		return 0
+	}
+
+	return offset % window
+}
+
+// startInWindow runs the function in the current window after the splay and jitter delay.
+func (l *Loop) startInWindow(ctx context.Context, wg *sync.WaitGroup) {
+	now := time.Now()
+
+	offset := l.startOffset(now)
+	if offset == 0 {
+		l.runFunction(ctx, wg)
+
+		return
+	}
+
+	l.mx.Lock()
+	l.scheduledTime = now
+	l.mx.Unlock()
+
+	l.logInfo(fmt.Sprintf("Start in the window after: [%s]", offset))
+	sendDuration(ctx, l.startDuration, &offset)
+}
+
+// Use adds middlewares wrapping the function, first one is the outermost.
+//   - Effects in the next run of the function.
+func (l *Loop) Use(middlewares ...Middleware) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	l.middlewares = append(l.middlewares, middlewares...)
+}
+
+// callFunction calls the function with the middlewares, recovers the panic if enabled.
+func (l *Loop) callFunction(ctx context.Context) error {
+	l.mx.RLock()
+	fn := chain(l.fn, l.middlewares)
+	l.mx.RUnlock()
+
+	if !l.recoverPanic {
+		return fn(ctx)
+	}
+
+	err := callRecover(ctx, fn)
+
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		l.logError("Function panicked", err, "stack", string(panicErr.Stack))
+	}
+
+	return err
+}
+
+// callHook calls the hook, recovers and logs the panic if enabled.
+func (l *Loop) callHook(hook func()) {
+	if !l.recoverPanic {
+		hook()
+
+		return
+	}
+
+	err := callRecover(context.Background(), func(context.Context) error {
+		hook()
+
+		return nil
+	})
+
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		l.logError("Hook panicked", err, "stack", string(panicErr.Stack))
+	}
+}
+
+// result returns the scheduling of the function after returning with the error.
+func (l *Loop) result(err error) (skipWindow bool, retryDelay time.Duration) {
+	if delay, ok := retryAfter(err); ok {
+		return false, delay
+	}
+
+	switch {
+	case errors.Is(err, ErrStopUntilNextWindow):
+		return true, 0
+	case errors.Is(err, ErrSkip):
+		return !l.restartPolicy.restart(nil), 0
+	default:
+		return !l.restartPolicy.restart(err), 0
+	}
 }
 
 func (l *Loop) stopFunction() {
 	l.mx.Lock()
 	defer l.mx.Unlock()
 
+	// stopping function triggers exited when it returns
+	if l.isFunctionStopping {
+		return
+	}
+
 	// if function is not running, trigger exited to get the next start time
 	if !l.isFunctionRunning {
 		// trigger exited
-		l.exited <- struct{}{}
+		l.triggerExited()
 
 		return
 	}
 
 	l.isFunctionRunning = false
+	l.isFunctionStopping = true
+
+	l.closeNotice()
+
+	cancelFn, run := l.cancelFn, l.run
+	if l.stopGrace > 0 {
+		l.logInfo(fmt.Sprintf("Stop grace period: [%s]", l.stopGrace))
+		l.graceTimer = time.AfterFunc(l.stopGrace, func() { l.cancelFunction(cancelFn, run) })
+
+		return
+	}
+
+	l.cancelFunction(cancelFn, run)
+}
+
+// cancelFunction cancels the function context, reports if the run doesn't return in the stop timeout.
+func (l *Loop) cancelFunction(cancelFn context.CancelFunc, run uint64) {
+	cancelFn()
+
+	if l.stopTimeout <= 0 {
+		return
+	}
+
+	time.AfterFunc(l.stopTimeout, func() {
+		l.mx.RLock()
+		stopping := l.isFunctionStopping && l.run == run
+		l.mx.RUnlock()
 
-	l.cancelFn()
+		if stopping {
+			l.logError("Function ignores cancellation", fmt.Errorf("%w in %s", ErrStopTimeout, l.stopTimeout))
+		}
+	})
 }
 
 func (l *Loop) initializeTime(ctx context.Context, wg *sync.WaitGroup) {
-	v, _ := l.scheduleGroup.getStopTime(time.Now().Add(GapDurationStart))
+	v, _ := l.schedules().getStopTime(time.Now().Add(l.gapDurationStart))
 	if v != nil {
 		// function should run now
-		l.runFunction(ctx, wg)
+		l.startInWindow(ctx, wg)
 
 		return
 	}
 
 	// set next start time
-	l.exited <- struct{}{}
+	l.triggerExited()
 }
@@ -0,0 +1,1097 @@
+package hardloop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	// testPeriod is the period of the test windows, a window is open in the first half of the period.
+	testPeriod = 400 * time.Millisecond
+	// testGap is the gap duration of the loops in the test windows.
+	testGap = 40 * time.Millisecond
+	// testLate is the allowed delay of the timers of the loop.
+	testLate = 50 * time.Millisecond
+	// testTimeout is the deadline to wait an event of the loop.
+	testTimeout = 5 * time.Second
+)
+
+// testWindows returns a schedule group opening a window in the first half of every testPeriod.
+//   - Specs don't have sub-second intervals, schedules are set directly.
+func testWindows() *ScheduleGroup {
+	return &ScheduleGroup{
+		StartSchedules: []Schedule{&SpecSchedule{ConstantDelaySchedule: testPeriod}},
+		StopSchedules:  []Schedule{&SpecSchedule{ConstantDelaySchedule: testPeriod, Anchor: time.Unix(0, 0).Add(testPeriod / 2)}},
+	}
+}
+
+// testStopDelay returns the duration of t after the last stop time of the test windows.
+func testStopDelay(t time.Time) time.Duration {
+	return (t.Sub(time.Unix(0, 0)) - testPeriod/2) % testPeriod
+}
+
+// newTestLoop returns a loop running the function in the test windows with testGap and without logging.
+func newTestLoop(t *testing.T, fn func(ctx context.Context) error, opts ...LoopOption) *Loop {
+	t.Helper()
+
+	opts = append([]LoopOption{WithGapDurationStart(testGap), WithLogger(nil)}, opts...)
+
+	l, err := NewLoop(nil, nil, fn, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.scheduleGroup = testWindows()
+
+	return l
+}
+
+// runLoop runs the loop, returned function cancels and waits it.
+func runLoop(l *Loop) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := &sync.WaitGroup{}
+
+	l.Run(ctx, wg)
+
+	return func() {
+		cancel()
+		wg.Wait()
+	}
+}
+
+// receive returns the next value of the channel, fails the test after testTimeout.
+func receive[T any](t *testing.T, ch <-chan T) T {
+	t.Helper()
+
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(testTimeout):
+		t.Fatal("timeout waiting the loop")
+	}
+
+	var zero T
+
+	return zero
+}
+
+// testLogger sends the info messages and the logged errors to the channels without blocking.
+type testLogger struct {
+	infos chan string
+	errs  chan error
+}
+
+func newTestLogger() *testLogger {
+	return &testLogger{infos: make(chan string, 1), errs: make(chan error, 1)}
+}
+
+func (l *testLogger) Info(msg string, _ ...interface{}) {
+	select {
+	case l.infos <- msg:
+	default:
+	}
+}
+
+func (l *testLogger) Error(_ string, keysAndValues ...interface{}) {
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		if err, ok := keysAndValues[i+1].(error); ok && keysAndValues[i] == "error" {
+			select {
+			case l.errs <- err:
+			default:
+			}
+		}
+	}
+}
+
+func (l *testLogger) Debug(string, ...interface{}) {}
+func (l *testLogger) Warn(string, ...interface{})  {}
+
+// cancelledTimes returns a function sending the time its context is cancelled.
+func cancelledTimes(times chan<- time.Time) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		<-ctx.Done()
+
+		select {
+		case times <- time.Now():
+		default:
+		}
+
+		return nil
+	}
+}
+
+func TestLoop_GapDuration(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		gapStart time.Duration
+		gapStop  time.Duration
+	}{
+		{name: "gap start", gapStart: 40 * time.Millisecond},
+		{name: "gap start in the window", gapStart: 150 * time.Millisecond},
+		{name: "gap stop", gapStart: 40 * time.Millisecond, gapStop: 60 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cancelled := make(chan time.Time, 1)
+			l := newTestLoop(t, cancelledTimes(cancelled), WithGapDurationStart(tt.gapStart), WithGapDurationStop(tt.gapStop))
+
+			stop := runLoop(l)
+			defer stop()
+
+			// function is stopped the gap duration before the stop time, delayed with the stop gap
+			if d := testStopDelay(receive(t, cancelled).Add(tt.gapStart - tt.gapStop)); d >= testLate {
+				t.Errorf("function is stopped %s after the stop time with the gap durations", d)
+			}
+		})
+	}
+}
+
+// windowRuns receives the start times of the runs until the third window and returns the runs in the second one.
+//   - First window can be partial, the loop starts in any time.
+func windowRuns(t *testing.T, starts <-chan time.Time) int {
+	t.Helper()
+
+	second := receive(t, starts).Truncate(testPeriod).Add(testPeriod)
+
+	var runs int
+
+	for {
+		window := receive(t, starts).Truncate(testPeriod)
+		if window.After(second) {
+			return runs
+		}
+
+		if window.Equal(second) {
+			runs++
+		}
+	}
+}
+
+func TestLoop_RestartPolicy(t *testing.T) {
+	t.Parallel()
+
+	errFailed := errors.New("failed")
+
+	tests := []struct {
+		name     string
+		policy   RestartPolicy
+		err      error
+		wantOnce bool
+	}{
+		{name: "always", policy: RestartAlways},
+		{name: "never", policy: RestartNever, wantOnce: true},
+		{name: "on failure with failure", policy: RestartOnFailure, err: errFailed},
+		{name: "on failure with success", policy: RestartOnFailure, wantOnce: true},
+		{name: "skip is not failure", policy: RestartOnFailure, err: ErrSkip, wantOnce: true},
+		{name: "skip restarts", policy: RestartAlways, err: ErrSkip},
+		{name: "stop until next window", policy: RestartAlways, err: ErrStopUntilNextWindow, wantOnce: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			starts := make(chan time.Time, 100)
+			l := newTestLoop(t, func(context.Context) error {
+				select {
+				case starts <- time.Now():
+				default:
+				}
+
+				time.Sleep(10 * time.Millisecond)
+
+				return tt.err
+			}, WithRestartPolicy(tt.policy))
+
+			stop := runLoop(l)
+			defer stop()
+
+			if runs := windowRuns(t, starts); (runs == 1) != tt.wantOnce {
+				t.Errorf("function runs %d times in the window", runs)
+			}
+		})
+	}
+}
+
+func TestLoop_RetryAfter(t *testing.T) {
+	t.Parallel()
+
+	const delay = 60 * time.Millisecond
+
+	type run struct {
+		info  JobInfo
+		start time.Time
+	}
+
+	runs := make(chan run, 100)
+
+	l := newTestLoop(t, func(ctx context.Context) error {
+		info, _ := JobFromContext(ctx)
+
+		select {
+		case runs <- run{info: info, start: time.Now()}:
+		default:
+		}
+
+		return RetryAfter(delay)
+	})
+
+	stop := runLoop(l)
+	defer stop()
+
+	var retries int
+
+	prev := receive(t, runs)
+	for retries < 3 {
+		r := receive(t, runs)
+
+		// retry sees the window with the gap duration
+		if d := r.start.Sub(r.info.WindowStop.Add(-testGap)); d >= testLate {
+			t.Errorf("function retries %s after the stop time", d)
+		}
+
+		if r.info.WindowStart.Equal(prev.info.WindowStart) {
+			retries++
+
+			if d := r.start.Sub(prev.start); d < delay {
+				t.Errorf("function retries after %s, want %s", d, delay)
+			}
+		}
+
+		prev = r
+	}
+}
+
+func TestLoop_Permanent(t *testing.T) {
+	t.Parallel()
+
+	errFailed := errors.New("failed")
+
+	var runs int
+
+	l := newTestLoop(t, func(context.Context) error {
+		runs++
+
+		return Permanent(errFailed)
+	})
+
+	ctx, cancel := context.WithTimeout(t.Context(), testTimeout)
+	defer cancel()
+
+	err := l.RunWait(ctx)
+
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Reason != ExitPermanent || !errors.Is(err, errFailed) {
+		t.Fatalf("RunWait() = %v, want permanent exit", err)
+	}
+
+	if runs != 1 {
+		t.Errorf("function runs %d times, want 1", runs)
+	}
+}
+
+func TestLoop_Hooks(t *testing.T) {
+	t.Parallel()
+
+	errFailed := errors.New("failed")
+
+	var mx sync.Mutex
+	var events []string
+
+	var runs int
+
+	l, err := NewLoop(nil, nil, func(context.Context) error {
+		runs++
+		if runs == 1 {
+			return errFailed
+		}
+
+		return ErrCloseLoop
+	}, WithLogger(nil), WithHooks(Hooks{
+		OnStart: func(context.Context) {
+			mx.Lock()
+			defer mx.Unlock()
+
+			events = append(events, "start")
+		},
+		OnExit: func(_ context.Context, err error) {
+			mx.Lock()
+			defer mx.Unlock()
+
+			events = append(events, fmt.Sprintf("exit: %v", err))
+		},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	// function closes the loop in the second run
+	l.RunWait(ctx)
+
+	mx.Lock()
+	defer mx.Unlock()
+
+	want := []string{"start", "exit: failed", "start", "exit: close loop"}
+	if !slices.Equal(events, want) {
+		t.Errorf("hooks = %q, want %q", events, want)
+	}
+}
+
+func TestLoop_WithParser(t *testing.T) {
+	fn := func(context.Context) error { return nil }
+
+	l, err := NewLoop([]string{"0 0 1 * 1"}, []string{"0 1 1 * 1"}, fn, WithParser(Parser{DaySemantics: DayOr}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := l.ChangeStopSchedules([]string{"0 2 1 * 1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, schedule := range append(l.scheduleGroup.StartSchedules, l.scheduleGroup.StopSchedules...) {
+		if s := schedule.(*SpecSchedule); s.DaySemantics != DayOr {
+			t.Errorf("DaySemantics = %v, want DayOr", s.DaySemantics)
+		}
+	}
+
+	// every Monday or the first day of the month
+	next := l.scheduleGroup.StartSchedules[0].Next(time.Date(2024, 1, 2, 0, 0, 0, 0, time.Local))
+	if want := time.Date(2024, 1, 8, 0, 0, 0, 0, time.Local); !next.Equal(want) {
+		t.Errorf("Next() = %s, want %s", next, want)
+	}
+}
+
+func TestLoop_SetLoggerWhileRunning(t *testing.T) {
+	t.Parallel()
+
+	l, err := NewLoop(nil, nil, func(context.Context) error {
+		time.Sleep(time.Millisecond)
+
+		return nil
+	}, WithLogger(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	log := newTestLogger()
+
+	stop := runLoop(l)
+	defer stop()
+
+	// loop logs every run
+	for range 50 {
+		l.SetLogger(log)
+		l.SetLogger(nil)
+	}
+
+	l.SetLogger(log)
+	receive(t, log.infos)
+}
+
+func TestLoop_SetExclusionsWhileRunning(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan time.Time, 1)
+
+	l := newTestLoop(t, func(ctx context.Context) error {
+		select {
+		case started <- time.Now():
+		default:
+		}
+
+		<-ctx.Done()
+
+		return nil
+	})
+
+	stop := runLoop(l)
+	defer stop()
+
+	deadline := time.After(testTimeout)
+
+	// calendar without dates doesn't exclude the windows
+	for runs := 0; runs < 2; {
+		select {
+		case <-started:
+			runs++
+		case <-deadline:
+			t.Fatal("timeout waiting the loop")
+		case <-time.After(time.Millisecond):
+			l.SetExclusions(NewDateCalendar())
+		}
+	}
+}
+
+func TestLoop_JobFromContext(t *testing.T) {
+	t.Parallel()
+
+	type run struct {
+		info JobInfo
+		now  time.Time
+	}
+
+	runs := make(chan run, 10)
+
+	l := newTestLoop(t, func(ctx context.Context) error {
+		info, _ := JobFromContext(ctx)
+
+		select {
+		case runs <- run{info: info, now: time.Now()}:
+		default:
+		}
+
+		if info.Attempt == 1 {
+			return RetryAfter(30 * time.Millisecond)
+		}
+
+		return ErrStopUntilNextWindow
+	}, WithName("info"))
+
+	stop := runLoop(l)
+	defer stop()
+
+	// retry can pass the first window, wait the retry in the same window
+	var first run
+	for {
+		r := receive(t, runs)
+		if r.info.Attempt == 2 && first.info.Attempt == 1 && r.info.WindowStart.Equal(first.info.WindowStart) {
+			break
+		}
+
+		first = r
+	}
+
+	info := first.info
+	if info.Name != "info" || info.ScheduledTime.IsZero() {
+		t.Errorf("JobFromContext() = %+v, want name and scheduled time", info)
+	}
+
+	if !info.WindowStart.Equal(info.WindowStart.Truncate(testPeriod)) || !info.WindowStop.Equal(info.WindowStart.Add(testPeriod/2)) {
+		t.Errorf("window = [%s, %s], want the first half of the test period", info.WindowStart, info.WindowStop)
+	}
+
+	// function can start the gap duration before the window
+	if remaining := info.Remaining(first.now); remaining <= 0 || remaining > testPeriod/2+testGap {
+		t.Errorf("Remaining() = %s, want in the window", remaining)
+	}
+}
+
+func TestLoop_AttemptReset(t *testing.T) {
+	t.Parallel()
+
+	// attempts of the third run in a window
+	attempts := make(chan int, 1)
+
+	var window time.Time
+	var runs int
+
+	l := newTestLoop(t, func(ctx context.Context) error {
+		info, _ := JobFromContext(ctx)
+		if !info.WindowStart.Equal(window) {
+			window, runs = info.WindowStart, 0
+		}
+
+		runs++
+
+		switch runs {
+		case 1:
+			return RetryAfter(10 * time.Millisecond)
+		case 2:
+			// restart after the retry
+			return nil
+		default:
+			select {
+			case attempts <- info.Attempt:
+			default:
+			}
+
+			return ErrStopUntilNextWindow
+		}
+	})
+
+	stop := runLoop(l)
+	defer stop()
+
+	if attempt := receive(t, attempts); attempt != 1 {
+		t.Errorf("Attempt = %d after a restart, want 1", attempt)
+	}
+}
+
+// noticeRun is a run of the function stopped by the loop.
+type noticeRun struct {
+	info                JobInfo
+	start, notice, done time.Time
+}
+
+// noticeRuns returns a function sending its runs after its context is cancelled, notice is zero if it is not closed before.
+func noticeRuns(runs chan<- noticeRun) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		r := noticeRun{start: time.Now()}
+		r.info, _ = JobFromContext(ctx)
+
+		select {
+		case <-StopNotice(ctx):
+			r.notice = time.Now()
+			<-ctx.Done()
+		case <-ctx.Done():
+		}
+
+		r.done = time.Now()
+
+		select {
+		case runs <- r:
+		default:
+		}
+
+		return nil
+	}
+}
+
+func TestLoop_StopNotice(t *testing.T) {
+	t.Parallel()
+
+	const notice = 100 * time.Millisecond
+
+	runs := make(chan noticeRun, 1)
+	l := newTestLoop(t, noticeRuns(runs), WithStopNotice(notice))
+
+	stop := runLoop(l)
+	defer stop()
+
+	// skip the runs started in the notice period
+	r := receive(t, runs)
+	for r.notice.IsZero() || r.notice.Sub(r.start) < testLate {
+		r = receive(t, runs)
+	}
+
+	if d := r.done.Sub(r.notice); d < notice-testLate || d >= notice+testLate {
+		t.Errorf("notice is closed %s before the cancellation, want %s", d, notice)
+	}
+}
+
+func TestLoop_StopGrace(t *testing.T) {
+	t.Parallel()
+
+	const grace = 100 * time.Millisecond
+
+	runs := make(chan noticeRun, 1)
+	l := newTestLoop(t, noticeRuns(runs), WithStopGrace(grace))
+
+	stop := runLoop(l)
+	defer stop()
+
+	r := receive(t, runs)
+
+	// notice is closed in the stop time, the gap duration before the window stop
+	if d := r.notice.Sub(r.info.WindowStop.Add(-testGap)); r.notice.IsZero() || d < 0 || d >= testLate {
+		t.Errorf("notice is closed %s after the stop time, want in the stop time", d)
+	}
+
+	if d := r.done.Sub(r.notice); d < grace || d >= grace+testLate {
+		t.Errorf("context is cancelled %s after the stop time, want after the grace period %s", d, grace)
+	}
+}
+
+func TestLoop_StopTimeout(t *testing.T) {
+	t.Parallel()
+
+	starts := make(chan JobInfo, 1)
+	release := make(chan struct{})
+
+	l := newTestLoop(t, func(ctx context.Context) error {
+		info, _ := JobFromContext(ctx)
+
+		select {
+		case starts <- info:
+		default:
+		}
+
+		// ignores the cancellation
+		<-release
+
+		return nil
+	}, WithStopTimeout(50*time.Millisecond))
+
+	log := newTestLogger()
+	l.SetLogger(log)
+
+	stop := runLoop(l)
+	defer stop()
+
+	info := receive(t, starts)
+
+	if err := receive(t, log.errs); !errors.Is(err, ErrStopTimeout) {
+		t.Fatalf("logged error = %v, want ErrStopTimeout", err)
+	}
+
+	if !l.IsFunctionStopping() || l.IsFunctionRunning() {
+		t.Error("function ignoring the cancellation should be stopping")
+	}
+
+	// function is not started in the next window until it returns
+	select {
+	case <-starts:
+		t.Fatal("function starts before the cancelled run returns")
+	case <-time.After(time.Until(info.WindowStart.Add(testPeriod + testLate))):
+	}
+
+	released := time.Now()
+	close(release)
+
+	if info := receive(t, starts); !info.ScheduledTime.After(released) {
+		t.Errorf("function starts at %s before the cancelled run returns at %s", info.ScheduledTime, released)
+	}
+}
+
+// newBlockingLoop returns a loop without windows running the function until it is cancelled.
+func newBlockingLoop(t *testing.T, started chan<- struct{}) *Loop {
+	t.Helper()
+
+	l, err := NewLoop(nil, nil, func(ctx context.Context) error {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+
+		<-ctx.Done()
+
+		return ctx.Err()
+	}, WithLogger(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return l
+}
+
+func TestLoop_StopWait(t *testing.T) {
+	t.Parallel()
+
+	t.Run("run again", func(t *testing.T) {
+		started := make(chan struct{}, 1)
+		l := newBlockingLoop(t, started)
+
+		for range 2 {
+			l.Run(t.Context(), &sync.WaitGroup{})
+			receive(t, started)
+
+			if !l.IsLoopRunning() || !l.IsFunctionRunning() {
+				t.Fatal("loop and function should be running")
+			}
+
+			l.Stop()
+			l.Wait()
+
+			if l.IsLoopRunning() || l.IsFunctionRunning() {
+				t.Fatal("loop and function should be stopped")
+			}
+		}
+	})
+
+	t.Run("run after stop", func(t *testing.T) {
+		started := make(chan struct{}, 1)
+		l := newBlockingLoop(t, started)
+
+		l.Run(t.Context(), &sync.WaitGroup{})
+		receive(t, started)
+
+		// Run waits the exit of the stopping loop
+		l.Stop()
+		l.Run(t.Context(), &sync.WaitGroup{})
+		receive(t, started)
+
+		if !l.IsLoopRunning() {
+			t.Fatal("loop should be running")
+		}
+
+		l.Stop()
+		l.Wait()
+	})
+
+	t.Run("done", func(t *testing.T) {
+		started := make(chan struct{}, 1)
+		l := newBlockingLoop(t, started)
+
+		// channel before the first run is closed after the first exit
+		done := l.Done()
+
+		l.Run(t.Context(), &sync.WaitGroup{})
+		receive(t, started)
+
+		select {
+		case <-done:
+			t.Fatal("Done is closed while running")
+		default:
+		}
+
+		l.Stop()
+		receive(t, done)
+
+		// new channel for the next run
+		l.Run(t.Context(), &sync.WaitGroup{})
+		receive(t, started)
+
+		select {
+		case <-l.Done():
+			t.Fatal("Done of the previous run is returned")
+		default:
+		}
+
+		l.Stop()
+		receive(t, l.Done())
+	})
+
+	t.Run("stop before run", func(t *testing.T) {
+		started := make(chan struct{}, 1)
+		l := newBlockingLoop(t, started)
+
+		l.Stop()
+
+		l.Run(t.Context(), &sync.WaitGroup{})
+		receive(t, started)
+
+		l.Stop()
+		receive(t, l.Done())
+	})
+
+	t.Run("stop twice", func(t *testing.T) {
+		started := make(chan struct{}, 1)
+		l := newBlockingLoop(t, started)
+
+		wg := &sync.WaitGroup{}
+		l.Run(t.Context(), wg)
+		receive(t, started)
+
+		l.Stop()
+		l.Stop()
+		receive(t, l.Done())
+		l.Stop()
+
+		// wait group of Run is done with the loop
+		wg.Wait()
+	})
+}
+
+func TestLoop_ExitReason(t *testing.T) {
+	t.Parallel()
+
+	errFailed := errors.New("failed")
+
+	tests := []struct {
+		name    string
+		err     error
+		stop    func(cancel context.CancelFunc, l *Loop)
+		want    ExitReason
+		wantErr error
+	}{
+		{
+			name: "canceled",
+			stop: func(cancel context.CancelFunc, _ *Loop) { cancel() },
+			want: ExitCanceled,
+		},
+		{
+			name: "stopped",
+			stop: func(_ context.CancelFunc, l *Loop) { l.Stop() },
+			want: ExitStopped,
+		},
+		{
+			name:    "closed",
+			err:     ErrCloseLoop,
+			want:    ExitClosed,
+			wantErr: ErrCloseLoop,
+		},
+		{
+			name:    "permanent",
+			err:     Permanent(errFailed),
+			want:    ExitPermanent,
+			wantErr: errFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			started := make(chan struct{}, 1)
+
+			l, err := NewLoop(nil, nil, func(ctx context.Context) error {
+				select {
+				case started <- struct{}{}:
+				default:
+				}
+
+				if tt.err != nil {
+					return tt.err
+				}
+
+				<-ctx.Done()
+
+				return nil
+			}, WithLogger(nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx, cancel := context.WithCancel(t.Context())
+			defer cancel()
+
+			l.Run(ctx, &sync.WaitGroup{})
+			receive(t, started)
+
+			if tt.stop != nil {
+				tt.stop(cancel, l)
+			}
+
+			receive(t, l.Done())
+
+			var exitErr *ExitError
+			if !errors.As(l.Err(), &exitErr) {
+				t.Fatalf("Err() = %v, want *ExitError", l.Err())
+			}
+
+			if exitErr.Reason != tt.want {
+				t.Errorf("reason = %v, want %v", exitErr.Reason, tt.want)
+			}
+
+			if tt.wantErr != nil && !errors.Is(l.Err(), tt.wantErr) {
+				t.Errorf("Err() = %v, want %v", l.Err(), tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("reset on run", func(t *testing.T) {
+		started := make(chan struct{}, 1)
+		l := newBlockingLoop(t, started)
+
+		l.Run(t.Context(), &sync.WaitGroup{})
+		receive(t, started)
+		l.Stop()
+		receive(t, l.Done())
+
+		if l.Err() == nil {
+			t.Fatal("Err() should report the exit")
+		}
+
+		l.Run(t.Context(), &sync.WaitGroup{})
+		receive(t, started)
+
+		if err := l.Err(); err != nil {
+			t.Fatalf("Err() = %v while running", err)
+		}
+
+		l.Stop()
+		receive(t, l.Done())
+	})
+
+	t.Run("run wait", func(t *testing.T) {
+		l, err := NewLoop(nil, nil, func(context.Context) error {
+			return ErrCloseLoop
+		}, WithLogger(nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := l.RunWait(t.Context()); !errors.Is(err, ErrCloseLoop) {
+			t.Fatalf("RunWait() = %v, want %v", err, ErrCloseLoop)
+		}
+	})
+}
+
+func TestLoop_SplayInWindow(t *testing.T) {
+	t.Parallel()
+
+	// splay is bigger than the window
+	if offset := splayOffset("ba", time.Hour); offset < testPeriod {
+		t.Fatalf("splayOffset() = %v, test needs a bigger offset than the window", offset)
+	}
+
+	type run struct {
+		start, done time.Time
+	}
+
+	runs := make(chan run, 1)
+
+	l := newTestLoop(t, func(ctx context.Context) error {
+		r := run{start: time.Now()}
+		<-ctx.Done()
+		r.done = time.Now()
+
+		select {
+		case runs <- r:
+		default:
+		}
+
+		return nil
+	}, WithName("ba"), WithSplay(time.Hour))
+
+	stop := runLoop(l)
+	defer stop()
+
+	for range 2 {
+		r := receive(t, runs)
+
+		// function can start the gap duration before the window
+		if untilStop := testPeriod - testStopDelay(r.start); untilStop > testPeriod/2+testGap {
+			t.Errorf("function starts out of the window at %s", r.start)
+		}
+
+		if d := r.done.Sub(r.start); d > testPeriod/2+testGap+testLate {
+			t.Errorf("function started at %s runs %s, stop time is missed", r.start, d)
+		}
+	}
+}
+
+func TestLoop_Recover(t *testing.T) {
+	t.Parallel()
+
+	runs := make(chan struct{}, 1)
+
+	l, err := NewLoop(nil, nil, func(context.Context) error {
+		select {
+		case runs <- struct{}{}:
+		default:
+		}
+
+		// panic is a failure, not a request to close the loop
+		panic(ErrCloseLoop)
+	}, WithRecover(), WithLogger(nil), WithHooks(Hooks{
+		OnStart: func(context.Context) { panic("start hook") },
+		OnExit:  func(context.Context, error) { panic("exit hook") },
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	l.Run(ctx, &sync.WaitGroup{})
+
+	// function restarts after panic
+	for range 2 {
+		receive(t, runs)
+	}
+
+	cancel()
+	receive(t, l.Done())
+
+	var exitErr *ExitError
+	if err := l.Err(); !errors.As(err, &exitErr) || exitErr.Reason != ExitCanceled {
+		t.Errorf("Err() = %v, want canceled exit", err)
+	}
+}
+
+func TestLoop_StopInGrace(t *testing.T) {
+	t.Parallel()
+
+	notified := make(chan struct{}, 1)
+
+	l := newTestLoop(t, func(ctx context.Context) error {
+		<-StopNotice(ctx)
+
+		select {
+		case notified <- struct{}{}:
+		default:
+		}
+
+		<-ctx.Done()
+
+		return nil
+	}, WithStopGrace(time.Hour))
+
+	l.Run(t.Context(), &sync.WaitGroup{})
+
+	receive(t, notified)
+
+	// stop doesn't wait the grace period
+	l.Stop()
+	receive(t, l.Done())
+
+	l.mx.RLock()
+	defer l.mx.RUnlock()
+
+	if l.graceTimer != nil {
+		t.Error("grace timer is not stopped")
+	}
+}
+
+func TestLoop_SplayInWindowStart(t *testing.T) {
+	t.Parallel()
+
+	const splay = 40 * time.Millisecond
+
+	offset := splayOffset("a", splay)
+	if offset < splay/2 {
+		t.Fatalf("splayOffset() = %v, test needs a bigger offset", offset)
+	}
+
+	starts := make(chan time.Time, 2)
+
+	l := newTestLoop(t, func(context.Context) error {
+		select {
+		case starts <- time.Now():
+		default:
+		}
+
+		return nil
+	}, WithName("a"), WithSplay(splay))
+
+	// start and restart in the window are delayed too
+	time.Sleep(time.Until(time.Now().Truncate(testPeriod).Add(testPeriod)))
+
+	prev := time.Now()
+
+	stop := runLoop(l)
+	defer stop()
+
+	for range 2 {
+		start := receive(t, starts)
+		if d := start.Sub(prev); d < offset {
+			t.Errorf("function starts %s after the previous start, want the splay %s", d, offset)
+		}
+
+		prev = start
+	}
+}
+
+func TestLoop_SendStopUnlocked(t *testing.T) {
+	t.Parallel()
+
+	l := newTestLoop(t, func(ctx context.Context) error {
+		<-ctx.Done()
+
+		return nil
+	})
+
+	// stop listener is busy, sending the stop time blocks
+	l.stopDuration = make(chan *time.Duration)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	wg := &sync.WaitGroup{}
+
+	defer wg.Wait()
+	defer cancel()
+
+	go l.runFunction(ctx, wg)
+
+	// loop is not locked while sending, stop listener can lock it to stop the function
+	running := make(chan struct{})
+	go func() {
+		defer close(running)
+
+		for !l.IsFunctionRunning() {
+			time.Sleep(time.Millisecond)
+		}
+	}()
+
+	receive(t, running)
+}
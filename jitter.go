@@ -0,0 +1,29 @@
+package hardloop
+
+import (
+	"hash/fnv"
+	"math/rand/v2"
+	"time"
+)
+
+// splayOffset returns a deterministic offset in [0, splay) from the hash of the name.
+//   - Same name gets the same offset in every run and process.
+func splayOffset(name string, splay time.Duration) time.Duration {
+	if splay <= 0 {
+		return 0
+	}
+
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(name))
+
+	return time.Duration(h.Sum64() % uint64(splay))
+}
+
+// jitterOffset returns a random offset in [0, jitter).
+func jitterOffset(jitter time.Duration) time.Duration {
+	if jitter <= 0 {
+		return 0
+	}
+
+	return rand.N(jitter) //nolint:gosec // no need crypto random for jitter
+}
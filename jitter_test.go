@@ -0,0 +1,46 @@
+package hardloop
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSplayOffset(t *testing.T) {
+	splay := 10 * time.Minute
+
+	offsets := map[time.Duration]struct{}{}
+	for _, name := range []string{"report", "cleanup", "sync", "backup"} {
+		offset := splayOffset(name, splay)
+		if offset < 0 || offset >= splay {
+			t.Fatalf("splayOffset(%q) = %v, want in [0, %v)", name, offset, splay)
+		}
+
+		if again := splayOffset(name, splay); again != offset {
+			t.Fatalf("splayOffset(%q) is not deterministic, got %v and %v", name, offset, again)
+		}
+
+		offsets[offset] = struct{}{}
+	}
+
+	if len(offsets) == 1 {
+		t.Errorf("splayOffset returns the same offset for different names")
+	}
+
+	if offset := splayOffset("report", 0); offset != 0 {
+		t.Errorf("splayOffset without splay = %v, want 0", offset)
+	}
+}
+
+func TestJitterOffset(t *testing.T) {
+	jitter := time.Second
+
+	for range 100 {
+		if offset := jitterOffset(jitter); offset < 0 || offset >= jitter {
+			t.Fatalf("jitterOffset() = %v, want in [0, %v)", offset, jitter)
+		}
+	}
+
+	if offset := jitterOffset(0); offset != 0 {
+		t.Errorf("jitterOffset without jitter = %v, want 0", offset)
+	}
+}
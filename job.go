@@ -4,20 +4,35 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"slices"
 	"sync"
 	"time"
 )
 
-var ErrCronAlreadyRunning = errors.New("cron already running")
+var (
+	ErrCronAlreadyRunning = errors.New("cron already running")
+	// ErrRemoveJob is returned by the job function to remove the job from the cron, like ErrCloseLoop.
+	//   - Removed job is not started again after Stop and Start.
+	ErrRemoveJob = errors.New("remove job")
+)
 
 type cronJob struct {
+	// Jobs are the scheduled jobs, don't change them after Start.
+	//   - Removed jobs are kept in the list, they are not started again, see Removed.
 	Jobs []Cron
 
 	started bool
 	m       sync.Mutex
+	// jobsMx guards the schedules of Jobs, middlewares and removed while the jobs are removing themselves.
+	//   - m is held in Start and Stop, Start returns ErrCronAlreadyRunning if it is locked.
+	jobsMx sync.Mutex
+	// removed are the ids of the removed jobs.
+	removed map[int]struct{}
 	wg      sync.WaitGroup
 	cancel  context.CancelFunc
 	log     Logger
+	// middlewares wrap the functions of all jobs.
+	middlewares []Middleware
 }
 
 type Cron struct {
@@ -25,21 +40,32 @@ type Cron struct {
 	Name  string
 	Func  func(ctx context.Context) error
 	Specs []string
+	// Exclusions skips the runs in the excluded dates.
+	Exclusions []Calendar
+	// Location is used for the specs without CRON_TZ or TZ prefix.
+	//   - nil uses the location set with SetLocation, the local time if it is not set.
+	Location *time.Location
+	// Splay delays the runs with a fixed offset in [0, Splay) derived from the Name.
+	//   - Spreads the jobs having the same spec.
+	Splay time.Duration
+	// Jitter delays each run with a random offset in [0, Jitter).
+	Jitter time.Duration
+	// Recover recovers the panics of the Func and logs them as PanicError.
+	Recover bool
+	// Middlewares wrap the Func of the job, inside of the middlewares of the cron.
+	Middlewares []Middleware
 
 	schedules []Schedule
+	// id identifies the job to remove.
+	id int
 }
 
 func NewCron(crons ...Cron) (*cronJob, error) {
 	jobs := make([]Cron, 0, len(crons))
 	for _, cron := range crons {
-		schedules := make([]Schedule, 0, len(cron.Specs))
-		for _, spec := range cron.Specs {
-			startSchedule, err := ParseStandard(spec)
-			if err != nil {
-				return nil, err
-			}
-
-			schedules = append(schedules, startSchedule)
+		schedules, err := cron.parse(cron.Location)
+		if err != nil {
+			return nil, err
 		}
 
 		if len(schedules) == 0 {
@@ -47,10 +73,17 @@ func NewCron(crons ...Cron) (*cronJob, error) {
 		}
 
 		jobs = append(jobs, Cron{
-			Name:      cron.Name,
-			Func:      cron.Func,
-			Specs:     cron.Specs,
-			schedules: schedules,
+			Name:        cron.Name,
+			Func:        cron.Func,
+			Specs:       cron.Specs,
+			Exclusions:  cron.Exclusions,
+			Location:    cron.Location,
+			Splay:       cron.Splay,
+			Jitter:      cron.Jitter,
+			Recover:     cron.Recover,
+			Middlewares: cron.Middlewares,
+			schedules:   schedules,
+			id:          len(jobs),
 		})
 	}
 
@@ -64,6 +97,37 @@ func (c *cronJob) SetLogger(log Logger) {
 	c.log = log
 }
 
+// SetLocation sets the location of the specs without CRON_TZ or TZ prefix for the jobs without Location.
+//   - Call before Start, effects in the next start.
+func (c *cronJob) SetLocation(loc *time.Location) error {
+	c.jobsMx.Lock()
+	defer c.jobsMx.Unlock()
+
+	for i, job := range c.Jobs {
+		if job.Location != nil {
+			continue
+		}
+
+		schedules, err := job.parse(loc)
+		if err != nil {
+			return err
+		}
+
+		c.Jobs[i].schedules = schedules
+	}
+
+	return nil
+}
+
+// Use adds middlewares wrapping the functions of all jobs, first one is the outermost.
+//   - Call before Start, effects in the next start.
+func (c *cronJob) Use(middlewares ...Middleware) {
+	c.jobsMx.Lock()
+	defer c.jobsMx.Unlock()
+
+	c.middlewares = append(c.middlewares, middlewares...)
+}
+
 // Start starts the cron job, running each job according to its schedule.
 //   - If the cron job is already running, it returns an error.
 func (c *cronJob) Start(ctx context.Context) error {
@@ -80,24 +144,45 @@ func (c *cronJob) Start(ctx context.Context) error {
 	ctx, cancel := context.WithCancel(ctx)
 	c.cancel = cancel
 
-	for _, job := range c.Jobs {
+	c.jobsMx.Lock()
+	jobs, middlewares := slices.Clone(c.Jobs), slices.Clone(c.middlewares)
+	c.jobsMx.Unlock()
+
+	for _, job := range jobs {
+		if c.isRemoved(job.id) {
+			continue
+		}
+
 		c.wg.Add(1)
 
+		fn := chain(job.Func, append(slices.Clone(middlewares), job.Middlewares...))
+
 		if c.log != nil {
 			c.log.Info("add cron job", "job", job.Name, "specs", job.Specs)
 		}
 
-		go func(job Cron) {
+		go func(job Cron, fn JobFunc) {
 			defer c.wg.Done()
 
-			var nextTime time.Time
+			// scheduledTime is the time found in the specs, the job runs offset later.
+			var scheduledTime time.Time
+			var offset, retryDelay time.Duration
+			var retry bool
+			var attempt int
 			for {
-				now := time.Now()
-				if !nextTime.IsZero() && nextTime.After(now) {
-					now = nextTime
+				nextTime := time.Now().Add(retryDelay)
+				if !retry {
+					attempt = 0
+					now := time.Now().Add(-offset)
+					if !scheduledTime.IsZero() && scheduledTime.After(now) {
+						now = scheduledTime
+					}
+
+					scheduledTime = FindNext(job.schedules, now)
+					offset = job.offset()
+					nextTime = scheduledTime.Add(offset)
 				}
 
-				nextTime = FindNext(job.schedules, now)
 				until := time.Until(nextTime)
 
 				if until <= 0 {
@@ -105,7 +190,7 @@ func (c *cronJob) Start(ctx context.Context) error {
 				}
 
 				if c.log != nil {
-					c.log.Info("starting cron job", "job", job.Name, "spec", job.Specs, "next_run", nextTime, "remaining", until)
+					c.log.Info("starting cron job", "job", job.Name, "spec", job.Specs, "next_run", nextTime, "scheduled", scheduledTime, "remaining", until)
 				}
 
 				select {
@@ -120,19 +205,138 @@ func (c *cronJob) Start(ctx context.Context) error {
 						c.log.Info("running cron job", "job", job.Name)
 					}
 
-					if err := job.Func(ctx); err != nil {
+					attempt++
+					err := job.call(withJobInfo(ctx, JobInfo{
+						Name:          job.Name,
+						ScheduledTime: scheduledTime,
+						Attempt:       attempt,
+					}), fn)
+					if retryDelay, retry = retryAfter(err); retry {
+						// retry only before the next scheduled time
+						if next := FindNext(job.schedules, scheduledTime); !next.IsZero() && !time.Now().Add(retryDelay).Before(next) {
+							retry = false
+
+							if c.log != nil {
+								c.log.Info("retry passes the next scheduled time, skipping", "job", job.Name, "retry_after", retryDelay)
+							}
+
+							continue
+						}
+
+						if c.log != nil {
+							c.log.Info("retrying cron job", "job", job.Name, "retry_after", retryDelay)
+						}
+
+						continue
+					}
+
+					var panicErr *PanicError
+
+					switch {
+					case err == nil, errors.Is(err, ErrSkip), errors.Is(err, ErrStopUntilNextWindow):
+					case errors.Is(err, ErrCloseLoop), errors.Is(err, ErrRemoveJob):
+						if c.log != nil {
+							c.log.Info("removing cron job", "job", job.Name)
+						}
+
+						c.removeJob(job.id)
+
+						return
+					case isPermanent(err):
+						if c.log != nil {
+							c.log.Error("cron job failed permanently, removing", "job", job.Name, "error", err)
+						}
+
+						c.removeJob(job.id)
+
+						return
+					case errors.As(err, &panicErr):
+						if c.log != nil {
+							c.log.Error("cron job panicked", "job", job.Name, "error", err, "stack", string(panicErr.Stack))
+						}
+					default:
 						if c.log != nil {
 							c.log.Error("error running cron job", "job", job.Name, "error", err)
 						}
 					}
 				}
 			}
-		}(job)
+		}(job, fn)
 	}
 
 	return nil
 }
 
+// removeJob marks the job as removed, it is not started again in the next Start.
+func (c *cronJob) removeJob(id int) {
+	c.jobsMx.Lock()
+	defer c.jobsMx.Unlock()
+
+	if c.removed == nil {
+		c.removed = make(map[int]struct{})
+	}
+
+	c.removed[id] = struct{}{}
+}
+
+// Removed returns the jobs removed with ErrRemoveJob, ErrCloseLoop or a PermanentError.
+//   - Removed jobs are not started again in the next Start.
+func (c *cronJob) Removed() []Cron {
+	c.jobsMx.Lock()
+	defer c.jobsMx.Unlock()
+
+	var removed []Cron
+
+	for _, job := range c.Jobs {
+		if _, ok := c.removed[job.id]; ok {
+			removed = append(removed, job)
+		}
+	}
+
+	return removed
+}
+
+// isRemoved returns true if the job is removed.
+func (c *cronJob) isRemoved(id int) bool {
+	c.jobsMx.Lock()
+	defer c.jobsMx.Unlock()
+
+	_, ok := c.removed[id]
+
+	return ok
+}
+
+// parse returns the schedules of the specs in the location with the exclusions.
+func (c Cron) parse(loc *time.Location) ([]Schedule, error) {
+	parser := Parser{Location: loc}
+
+	schedules := make([]Schedule, 0, len(c.Specs))
+	for _, spec := range c.Specs {
+		startSchedule, err := parser.parse(spec)
+		if err != nil {
+			return nil, err
+		}
+
+		schedules = append(schedules, Exclude(startSchedule, c.Exclusions...))
+	}
+
+	return schedules, nil
+}
+
+// call calls the job function wrapped with the middlewares, recovers the panic if enabled.
+func (c Cron) call(ctx context.Context, fn JobFunc) error {
+	if c.Recover {
+		return callRecover(ctx, fn)
+	}
+
+	return fn(ctx)
+}
+
+// offset returns the delay of the next run from its scheduled time.
+func (c Cron) offset() time.Duration {
+	return splayOffset(c.Name, c.Splay) + jitterOffset(c.Jitter)
+}
+
 // Stop stops the cron job with cancel context and waits for all running jobs to finish.
 func (c *cronJob) Stop() {
 	c.m.Lock()
@@ -2,8 +2,12 @@ package hardloop_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/worldline-go/hardloop"
 )
@@ -34,3 +38,365 @@ func TestJob(t *testing.T) {
 
 	<-t.Context().Done()
 }
+
+func TestJob_RemoveJob(t *testing.T) {
+	var calls atomic.Int64
+
+	called := make(chan struct{}, 1)
+
+	cronJob, err := hardloop.NewCron(
+		hardloop.Cron{
+			Name: "OneShot",
+			Func: func(ctx context.Context) error {
+				calls.Add(1)
+				called <- struct{}{}
+
+				return hardloop.ErrRemoveJob
+			},
+			Specs: []string{"@every 1s"},
+		},
+		hardloop.Cron{
+			Name:  "Forever",
+			Func:  func(ctx context.Context) error { return nil },
+			Specs: []string{"@every 1h"},
+		},
+	)
+	if err != nil {
+		t.Fatalf("Failed to create cron job: %v", err)
+	}
+
+	cronJob.SetLogger(nil)
+
+	if err := cronJob.Start(t.Context()); err != nil {
+		t.Fatalf("Failed to start cron job: %v", err)
+	}
+
+	select {
+	case <-called:
+	case <-time.After(5 * time.Second):
+		t.Fatal("job is not called")
+	}
+
+	// removed job is not started again
+	time.Sleep(1500 * time.Millisecond)
+	cronJob.Stop()
+
+	if removed := cronJob.Removed(); len(removed) != 1 || removed[0].Name != "OneShot" {
+		t.Errorf("Removed() = %v, want OneShot", removed)
+	}
+
+	if err := cronJob.Start(t.Context()); err != nil {
+		t.Fatalf("Failed to start cron job: %v", err)
+	}
+
+	time.Sleep(1500 * time.Millisecond)
+	cronJob.Stop()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("removed job is called %d times, want 1", got)
+	}
+
+	if len(cronJob.Jobs) != 2 {
+		t.Errorf("Jobs = %v, want to keep the config of the jobs", cronJob.Jobs)
+	}
+}
+
+func TestJob_JobFromContext(t *testing.T) {
+	if _, ok := hardloop.JobFromContext(t.Context()); ok {
+		t.Fatal("JobFromContext() found info in a plain context")
+	}
+
+	infos := make(chan hardloop.JobInfo, 2)
+
+	cronJob, err := hardloop.NewCron(hardloop.Cron{
+		Name: "Info",
+		Func: func(ctx context.Context) error {
+			info, _ := hardloop.JobFromContext(ctx)
+			infos <- info
+
+			if info.Attempt == 1 {
+				return hardloop.RetryAfter(0)
+			}
+
+			return hardloop.ErrRemoveJob
+		},
+		Specs: []string{"@every 1s"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create cron job: %v", err)
+	}
+
+	cronJob.SetLogger(nil)
+
+	if err := cronJob.Start(t.Context()); err != nil {
+		t.Fatalf("Failed to start cron job: %v", err)
+	}
+	defer cronJob.Stop()
+
+	var got []hardloop.JobInfo
+	for range 2 {
+		select {
+		case info := <-infos:
+			got = append(got, info)
+		case <-time.After(5 * time.Second):
+			t.Fatal("job is not called")
+		}
+	}
+
+	if got[0].Name != "Info" || got[0].Attempt != 1 || got[1].Attempt != 2 {
+		t.Errorf("JobFromContext() = %+v, want attempts 1 and 2 of Info", got)
+	}
+
+	if !got[0].ScheduledTime.Equal(got[1].ScheduledTime) || got[0].ScheduledTime.IsZero() {
+		t.Errorf("retry should keep the scheduled time, got %v and %v", got[0].ScheduledTime, got[1].ScheduledTime)
+	}
+}
+
+// scheduleLogger sends the scheduled times of the jobs logged in the start.
+type scheduleLogger struct {
+	scheduled chan map[string]time.Time
+}
+
+func (l *scheduleLogger) Info(msg string, keysAndValues ...interface{}) {
+	if msg != "starting cron job" {
+		return
+	}
+
+	var job string
+	var scheduled time.Time
+
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		switch keysAndValues[i] {
+		case "job":
+			job, _ = keysAndValues[i+1].(string)
+		case "scheduled":
+			scheduled, _ = keysAndValues[i+1].(time.Time)
+		}
+	}
+
+	select {
+	case l.scheduled <- map[string]time.Time{job: scheduled}:
+	default:
+	}
+}
+
+func (l *scheduleLogger) Error(string, ...interface{}) {}
+func (l *scheduleLogger) Debug(string, ...interface{}) {}
+func (l *scheduleLogger) Warn(string, ...interface{})  {}
+
+func TestJob_SetLocation(t *testing.T) {
+	amsterdam, err := time.LoadLocation("Europe/Amsterdam")
+	if err != nil {
+		t.Fatalf("failed to load location: %v", err)
+	}
+
+	istanbul, err := time.LoadLocation("Europe/Istanbul")
+	if err != nil {
+		t.Fatalf("failed to load location: %v", err)
+	}
+
+	fn := func(context.Context) error { return nil }
+
+	cronJob, err := hardloop.NewCron(
+		hardloop.Cron{Name: "Default", Func: fn, Specs: []string{"0 7 * * *"}},
+		hardloop.Cron{Name: "Own", Func: fn, Specs: []string{"0 7 * * *"}, Location: istanbul},
+	)
+	if err != nil {
+		t.Fatalf("Failed to create cron job: %v", err)
+	}
+
+	if err := cronJob.SetLocation(amsterdam); err != nil {
+		t.Fatalf("SetLocation() error = %v", err)
+	}
+
+	log := &scheduleLogger{scheduled: make(chan map[string]time.Time, 2)}
+	cronJob.SetLogger(log)
+
+	if err := cronJob.Start(t.Context()); err != nil {
+		t.Fatalf("Failed to start cron job: %v", err)
+	}
+	defer cronJob.Stop()
+
+	want := map[string]*time.Location{"Default": amsterdam, "Own": istanbul}
+
+	for range want {
+		var scheduled map[string]time.Time
+
+		select {
+		case scheduled = <-log.scheduled:
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting the start of the jobs")
+		}
+
+		for job, at := range scheduled {
+			if hour := at.In(want[job]).Hour(); hour != 7 {
+				t.Errorf("job %s is scheduled at %s, want 07:00 in %s", job, at, want[job])
+			}
+		}
+	}
+}
+
+type errorCounter struct {
+	errors atomic.Int64
+}
+
+func (l *errorCounter) Error(string, ...interface{}) { l.errors.Add(1) }
+func (l *errorCounter) Info(string, ...interface{})  {}
+func (l *errorCounter) Debug(string, ...interface{}) {}
+func (l *errorCounter) Warn(string, ...interface{})  {}
+
+func TestJob_FunctionResults(t *testing.T) {
+	t.Parallel()
+
+	errFailed := errors.New("failed")
+
+	type call struct {
+		info hardloop.JobInfo
+		time time.Time
+	}
+
+	tests := []struct {
+		name       string
+		result     error
+		wantErrors bool
+		check      func(t *testing.T, calls []call)
+	}{
+		{
+			name:   "skip",
+			result: hardloop.ErrSkip,
+			check: func(t *testing.T, calls []call) {
+				if len(calls) < 2 {
+					t.Errorf("job is called %d times, want to run in every scheduled time", len(calls))
+				}
+			},
+		},
+		{
+			name:   "stop until next window",
+			result: hardloop.ErrStopUntilNextWindow,
+			check: func(t *testing.T, calls []call) {
+				if len(calls) < 2 {
+					t.Errorf("job is called %d times, want to run in every scheduled time", len(calls))
+				}
+			},
+		},
+		{
+			name:       "permanent",
+			result:     hardloop.Permanent(errFailed),
+			wantErrors: true,
+			check: func(t *testing.T, calls []call) {
+				if len(calls) != 1 {
+					t.Errorf("job is called %d times, want to be removed after the first call", len(calls))
+				}
+			},
+		},
+		{
+			name:   "retry before next scheduled time",
+			result: hardloop.RetryAfter(300 * time.Millisecond),
+			check: func(t *testing.T, calls []call) {
+				var retried bool
+				for _, c := range calls {
+					retried = retried || c.info.Attempt > 1
+					if c.time.Sub(c.info.ScheduledTime) >= time.Second {
+						t.Errorf("retry of %s runs at %s, after the next scheduled time", c.info.ScheduledTime, c.time)
+					}
+				}
+
+				if !retried {
+					t.Error("job is not retried")
+				}
+			},
+		},
+		{
+			name:   "retry after next scheduled time",
+			result: hardloop.RetryAfter(5 * time.Second),
+			check: func(t *testing.T, calls []call) {
+				if len(calls) < 2 {
+					t.Errorf("job is called %d times, want to run in every scheduled time", len(calls))
+				}
+
+				for _, c := range calls {
+					if c.info.Attempt != 1 {
+						t.Errorf("job is retried in attempt %d, retry passes the next scheduled time", c.info.Attempt)
+					}
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var mx sync.Mutex
+			var calls []call
+
+			cronJob, err := hardloop.NewCron(hardloop.Cron{
+				Name: "Result",
+				Func: func(ctx context.Context) error {
+					info, _ := hardloop.JobFromContext(ctx)
+
+					mx.Lock()
+					calls = append(calls, call{info: info, time: time.Now()})
+					mx.Unlock()
+
+					return tt.result
+				},
+				Specs: []string{"@every 1s"},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create cron job: %v", err)
+			}
+
+			log := &errorCounter{}
+			cronJob.SetLogger(log)
+
+			if err := cronJob.Start(t.Context()); err != nil {
+				t.Fatalf("Failed to start cron job: %v", err)
+			}
+
+			time.Sleep(2500 * time.Millisecond)
+			cronJob.Stop()
+
+			if got := log.errors.Load() > 0; got != tt.wantErrors {
+				t.Errorf("error logged = %v, want %v", got, tt.wantErrors)
+			}
+
+			mx.Lock()
+			defer mx.Unlock()
+
+			tt.check(t, calls)
+		})
+	}
+}
+
+func TestJob_UseWhileStarting(t *testing.T) {
+	cronJob, err := hardloop.NewCron(hardloop.Cron{
+		Name:  "Use",
+		Func:  func(ctx context.Context) error { return nil },
+		Specs: []string{"@every 1h"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create cron job: %v", err)
+	}
+
+	cronJob.SetLogger(nil)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		for range 100 {
+			cronJob.Use(func(fn hardloop.JobFunc) hardloop.JobFunc { return fn })
+		}
+	}()
+
+	// Use doesn't block Start
+	for range 100 {
+		if err := cronJob.Start(t.Context()); err != nil {
+			t.Fatalf("Start() error = %v", err)
+		}
+
+		cronJob.Stop()
+	}
+
+	<-done
+}
@@ -0,0 +1,106 @@
+package hardloop
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// JobFunc is the function run by Loop and cron jobs.
+type JobFunc func(ctx context.Context) error
+
+// Middleware wraps a JobFunc to add behavior around it.
+type Middleware func(JobFunc) JobFunc
+
+// MetricsRecorder records the runs of the functions.
+type MetricsRecorder interface {
+	// ObserveRun is called after every run with its duration and returned error.
+	ObserveRun(ctx context.Context, duration time.Duration, err error)
+}
+
+// chain wraps the function with the middlewares, first middleware is the outermost.
+func chain(fn JobFunc, middlewares []Middleware) JobFunc {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		fn = middlewares[i](fn)
+	}
+
+	return fn
+}
+
+// Recover returns a middleware recovering the panics of the function as PanicError.
+func Recover() Middleware {
+	return func(next JobFunc) JobFunc {
+		return func(ctx context.Context) error {
+			return callRecover(ctx, next)
+		}
+	}
+}
+
+// Timeout returns a middleware cancelling the context of the function after the duration.
+func Timeout(d time.Duration) Middleware {
+	return func(next JobFunc) JobFunc {
+		return func(ctx context.Context) error {
+			ctx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+
+			return next(ctx)
+		}
+	}
+}
+
+// Logging returns a middleware logging the start and the end of the function with its duration.
+//   - nil logger disables logging.
+func Logging(log Logger) Middleware {
+	return func(next JobFunc) JobFunc {
+		if log == nil {
+			return next
+		}
+
+		return func(ctx context.Context) error {
+			log.Info("function started")
+
+			start := time.Now()
+			err := next(ctx)
+			duration := time.Since(start)
+
+			if err != nil {
+				log.Error("function finished", "duration", duration, "error", err)
+			} else {
+				log.Info("function finished", "duration", duration)
+			}
+
+			return err
+		}
+	}
+}
+
+// Metrics returns a middleware reporting the duration and error of every run to the recorder.
+func Metrics(recorder MetricsRecorder) Middleware {
+	return func(next JobFunc) JobFunc {
+		return func(ctx context.Context) error {
+			start := time.Now()
+			err := next(ctx)
+			recorder.ObserveRun(ctx, time.Since(start), err)
+
+			return err
+		}
+	}
+}
+
+// SingleFlight returns a middleware allowing only one run of the function at the same time.
+//   - Runs started while another one is running return ErrSkip.
+//   - Share the same middleware between functions to not run them together.
+func SingleFlight() Middleware {
+	var running atomic.Bool
+
+	return func(next JobFunc) JobFunc {
+		return func(ctx context.Context) error {
+			if !running.CompareAndSwap(false, true) {
+				return ErrSkip
+			}
+			defer running.Store(false)
+
+			return next(ctx)
+		}
+	}
+}
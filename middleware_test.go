@@ -0,0 +1,150 @@
+package hardloop_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/worldline-go/hardloop"
+)
+
+func TestMiddleware(t *testing.T) {
+	t.Run("recover", func(t *testing.T) {
+		fn := hardloop.Recover()(func(ctx context.Context) error {
+			panic("boom")
+		})
+
+		var panicErr *hardloop.PanicError
+		if err := fn(t.Context()); !errors.As(err, &panicErr) || panicErr.Value != "boom" {
+			t.Errorf("Recover() error = %v, want PanicError", err)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		fn := hardloop.Timeout(10 * time.Millisecond)(func(ctx context.Context) error {
+			<-ctx.Done()
+
+			return ctx.Err()
+		})
+
+		if err := fn(t.Context()); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Timeout() error = %v, want %v", err, context.DeadlineExceeded)
+		}
+	})
+
+	t.Run("single flight", func(t *testing.T) {
+		started := make(chan struct{})
+		release := make(chan struct{})
+
+		fn := hardloop.SingleFlight()(func(ctx context.Context) error {
+			close(started)
+			<-release
+
+			return nil
+		})
+
+		done := make(chan error)
+		go func() { done <- fn(t.Context()) }()
+
+		<-started
+
+		if err := fn(t.Context()); !errors.Is(err, hardloop.ErrSkip) {
+			t.Errorf("SingleFlight() error = %v, want %v", err, hardloop.ErrSkip)
+		}
+
+		close(release)
+
+		if err := <-done; err != nil {
+			t.Errorf("SingleFlight() first run error = %v", err)
+		}
+	})
+
+	t.Run("logging without logger", func(t *testing.T) {
+		errFailed := errors.New("failed")
+
+		fn := hardloop.Logging(nil)(func(ctx context.Context) error {
+			return errFailed
+		})
+
+		if err := fn(t.Context()); !errors.Is(err, errFailed) {
+			t.Errorf("Logging(nil) error = %v, want %v", err, errFailed)
+		}
+	})
+
+	t.Run("metrics", func(t *testing.T) {
+		errFailed := errors.New("failed")
+		recorder := &testRecorder{}
+
+		fn := hardloop.Metrics(recorder)(func(ctx context.Context) error {
+			return errFailed
+		})
+
+		_ = fn(t.Context())
+
+		if recorder.runs != 1 || !errors.Is(recorder.err, errFailed) {
+			t.Errorf("Metrics() recorded %d runs with %v", recorder.runs, recorder.err)
+		}
+	})
+
+	t.Run("order", func(t *testing.T) {
+		var calls []string
+
+		middleware := func(name string) hardloop.Middleware {
+			return func(next hardloop.JobFunc) hardloop.JobFunc {
+				return func(ctx context.Context) error {
+					calls = append(calls, name)
+
+					return next(ctx)
+				}
+			}
+		}
+
+		called := make(chan struct{})
+
+		cronJob, err := hardloop.NewCron(hardloop.Cron{
+			Name: "Order",
+			Func: func(ctx context.Context) error {
+				calls = append(calls, "func")
+				close(called)
+
+				return hardloop.ErrRemoveJob
+			},
+			Specs:       []string{"@every 1s"},
+			Middlewares: []hardloop.Middleware{middleware("job")},
+		})
+		if err != nil {
+			t.Fatalf("Failed to create cron job: %v", err)
+		}
+
+		cronJob.SetLogger(nil)
+		cronJob.Use(middleware("first"), middleware("second"))
+
+		if err := cronJob.Start(t.Context()); err != nil {
+			t.Fatalf("Failed to start cron job: %v", err)
+		}
+
+		select {
+		case <-called:
+		case <-time.After(5 * time.Second):
+			t.Fatal("job is not called")
+		}
+
+		cronJob.Stop()
+
+		if got := strings.Join(calls, ","); got != "first,second,job,func" {
+			t.Errorf("calls = %s, want first,second,job,func", got)
+		}
+	})
+}
+
+type testRecorder struct {
+	runs int
+	err  error
+}
+
+func (r *testRecorder) ObserveRun(_ context.Context, _ time.Duration, err error) {
+	r.runs++
+	r.err = err
+}
@@ -0,0 +1,153 @@
+package hardloop
+
+import (
+	"context"
+	"time"
+)
+
+// LoopOption configures a Loop in the constructor.
+type LoopOption func(*Loop)
+
+// RestartPolicy is the handling of the function returning in its window.
+type RestartPolicy uint8
+
+const (
+	// RestartAlways runs the function again if it returns in its window.
+	RestartAlways RestartPolicy = iota
+	// RestartOnFailure runs the function again only if it returns an error.
+	RestartOnFailure
+	// RestartNever runs the function once in a window, waits the next start after the window.
+	RestartNever
+)
+
+// restart returns true if the function should run again after returning with the error.
+func (p RestartPolicy) restart(err error) bool {
+	switch p {
+	case RestartOnFailure:
+		return err != nil
+	case RestartNever:
+		return false
+	default:
+		return true
+	}
+}
+
+// Hooks are called around every run of the function.
+type Hooks struct {
+	// OnStart is called before the function with its context.
+	OnStart func(ctx context.Context)
+	// OnExit is called after the function with its context and returned error.
+	OnExit func(ctx context.Context, err error)
+}
+
+// WithName sets the name of the loop used in logs.
+func WithName(name string) LoopOption {
+	return func(l *Loop) {
+		l.name = name
+	}
+}
+
+// WithLogger sets the logger of the loop, nil disables logging.
+//   - Default is the default slog logger.
+func WithLogger(log Logger) LoopOption {
+	return func(l *Loop) {
+		l.SetLogger(log)
+	}
+}
+
+// WithParser sets the parser of the start and stop specs.
+//   - Used in the constructor and ChangeStartSchedules, ChangeStopSchedules.
+func WithParser(parser Parser) LoopOption {
+	return func(l *Loop) {
+		l.parser = parser
+	}
+}
+
+// WithLocation sets the location of the specs without CRON_TZ or TZ prefix.
+//   - Overrides the Location of the parser.
+func WithLocation(loc *time.Location) LoopOption {
+	return func(l *Loop) {
+		l.location = loc
+	}
+}
+
+// WithRestartPolicy sets the handling of the function returning in its window.
+//   - Default is RestartAlways.
+func WithRestartPolicy(policy RestartPolicy) LoopOption {
+	return func(l *Loop) {
+		l.restartPolicy = policy
+	}
+}
+
+// WithHooks sets the hooks called around every run of the function.
+func WithHooks(hooks Hooks) LoopOption {
+	return func(l *Loop) {
+		l.hooks = hooks
+	}
+}
+
+// WithRecover recovers the panics of the function and returns them as PanicError.
+//   - Panic is logged, passed to the hooks and the restart policy like other errors.
+//   - Panics of the hooks are also recovered and logged.
+func WithRecover() LoopOption {
+	return func(l *Loop) {
+		l.recoverPanic = true
+	}
+}
+
+// WithStopNotice closes the StopNotice channel of the function the duration before the stop time.
+//   - Gives time to the function to checkpoint before the cancellation.
+func WithStopNotice(d time.Duration) LoopOption {
+	return func(l *Loop) {
+		l.stopNotice = d
+	}
+}
+
+// WithStopGrace delays the cancellation of the function context the duration after the stop time.
+//   - StopNotice channel is closed in the stop time.
+func WithStopGrace(d time.Duration) LoopOption {
+	return func(l *Loop) {
+		l.stopGrace = d
+	}
+}
+
+// WithStopTimeout reports the function not returning the duration after its context is cancelled.
+//   - Function can't be killed, it is logged with ErrStopTimeout and not started again until it returns.
+func WithStopTimeout(d time.Duration) LoopOption {
+	return func(l *Loop) {
+		l.stopTimeout = d
+	}
+}
+
+// WithSplay delays the start times with a fixed offset in [0, splay) derived from the loop name.
+//   - Spreads the loops having the same start spec, set a name with WithName.
+//   - Splay and jitter are wrapped in the window to start before the stop time.
+//   - Starts and restarts in a running window are delayed too.
+func WithSplay(splay time.Duration) LoopOption {
+	return func(l *Loop) {
+		l.splay = splay
+	}
+}
+
+// WithJitter delays each start time with a random offset in [0, jitter).
+func WithJitter(jitter time.Duration) LoopOption {
+	return func(l *Loop) {
+		l.jitter = jitter
+	}
+}
+
+// WithGapDurationStart sets the gap duration to check start and stop times of the loop.
+//   - Default is the GapDurationStart value when the loop is created.
+func WithGapDurationStart(d time.Duration) LoopOption {
+	return func(l *Loop) {
+		l.gapDurationStart = d
+	}
+}
+
+// WithGapDurationStop sets the duration added to the stop time of the function.
+//   - Default is the GapDurationStop value when the loop is created.
+func WithGapDurationStop(d time.Duration) LoopOption {
+	return func(l *Loop) {
+		l.gapDurationStop = d
+	}
+}
@@ -1,18 +1,36 @@
 package hardloop
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
+
+// windowMergeLimit is the maximum number of overlapping windows merged to find a stop time.
+const windowMergeLimit = 1000
 
 type ScheduleGroup struct {
 	StartSchedules []Schedule
 	StopSchedules  []Schedule
+	// Duration is the run time after each start time.
+	//   - If set, StopSchedules are not used and stop time is derived from the start times.
+	Duration time.Duration
+	// WindowSchedules are start schedules paired with their stop schedules.
+	//   - If set, StartSchedules, StopSchedules and Duration are not used.
+	WindowSchedules []WindowSchedule
+	// Exclusions skips the start times in the excluded dates.
+	Exclusions []Calendar
 }
 
 func NewSchedule(startSpec, endSpec []string) (*ScheduleGroup, error) {
+	return newSchedule(Parser{}, startSpec, endSpec)
+}
+
+func newSchedule(parser Parser, startSpec, endSpec []string) (*ScheduleGroup, error) {
 	startSchedules := make([]Schedule, 0, len(startSpec))
 	stopSchedules := make([]Schedule, 0, len(endSpec))
 
 	for _, spec := range startSpec {
-		startSchedule, err := ParseStandard(spec)
+		startSchedule, err := parser.parse(spec)
 		if err != nil {
 			return nil, err
 		}
@@ -21,7 +39,7 @@ func NewSchedule(startSpec, endSpec []string) (*ScheduleGroup, error) {
 	}
 
 	for _, spec := range endSpec {
-		stopSchedule, err := ParseStandard(spec)
+		stopSchedule, err := parser.parse(spec)
 		if err != nil {
 			return nil, err
 		}
@@ -35,9 +53,52 @@ func NewSchedule(startSpec, endSpec []string) (*ScheduleGroup, error) {
 	}, nil
 }
 
+// NewScheduleDuration returns a schedule group running duration long after each start spec.
+//   - Overlapping runs are merged in one window.
+func NewScheduleDuration(startSpec []string, duration time.Duration) (*ScheduleGroup, error) {
+	return newScheduleDuration(Parser{}, startSpec, duration)
+}
+
+func newScheduleDuration(parser Parser, startSpec []string, duration time.Duration) (*ScheduleGroup, error) {
+	if duration <= 0 {
+		return nil, fmt.Errorf("duration should be positive: %s", duration)
+	}
+
+	scheduleGroup, err := newSchedule(parser, startSpec, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduleGroup.Duration = duration
+
+	return scheduleGroup, nil
+}
+
+// startSchedules returns the start schedules with exclusions.
+func (l *ScheduleGroup) startSchedules() []Schedule {
+	startSchedules := l.StartSchedules
+	if len(l.WindowSchedules) > 0 {
+		startSchedules = make([]Schedule, 0, len(l.WindowSchedules))
+		for _, window := range l.WindowSchedules {
+			startSchedules = append(startSchedules, window.Start)
+		}
+	}
+
+	if len(l.Exclusions) == 0 {
+		return startSchedules
+	}
+
+	schedules := make([]Schedule, 0, len(startSchedules))
+	for _, schedule := range startSchedules {
+		schedules = append(schedules, Exclude(schedule, l.Exclusions...))
+	}
+
+	return schedules
+}
+
 // getStartTime if return nil, start now.
 func (l *ScheduleGroup) getStartTime(now time.Time) (*time.Time, error) {
-	nextStart := FindNext(l.StartSchedules, now)
+	nextStart := FindNext(l.startSchedules(), now)
 
 	if nextStart.IsZero() {
 		return nil, errTimeNotSet
@@ -48,6 +109,14 @@ func (l *ScheduleGroup) getStartTime(now time.Time) (*time.Time, error) {
 
 // getStopTime if return nil, stop now.
 func (l *ScheduleGroup) getStopTime(now time.Time) (*time.Time, error) {
+	if len(l.WindowSchedules) > 0 {
+		return l.getWindowStopTime(now), nil
+	}
+
+	if l.Duration > 0 {
+		return l.getDurationStopTime(now), nil
+	}
+
 	prevStop := FindPrev(l.StopSchedules, now)
 
 	if prevStop.IsZero() {
@@ -55,7 +124,7 @@ func (l *ScheduleGroup) getStopTime(now time.Time) (*time.Time, error) {
 		return nil, errTimeNotSet
 	}
 
-	prevStart := FindPrev(l.StartSchedules, now)
+	prevStart := FindPrev(l.startSchedules(), now)
 
 	// if prevStop is after prevStart, then we should stop the loop
 	if !prevStart.IsZero() && prevStop.After(prevStart) {
@@ -73,6 +142,30 @@ func (l *ScheduleGroup) getStopTime(now time.Time) (*time.Time, error) {
 	return &nextStop, nil
 }
 
+// getDurationStopTime returns the end of the window started in the last duration, nil if not in a window.
+func (l *ScheduleGroup) getDurationStopTime(now time.Time) *time.Time {
+	startSchedules := l.startSchedules()
+
+	prevStart := FindPrev(startSchedules, now)
+	if prevStart.IsZero() {
+		return nil
+	}
+
+	stop := prevStart.Add(l.Duration)
+	if !stop.After(now) {
+		return nil
+	}
+
+	// extend the window with the next starts before the stop time
+	nextStart := FindNext(startSchedules, now)
+	for i := 0; i < windowMergeLimit && !nextStart.IsZero() && !nextStart.After(stop); i++ {
+		stop = nextStart.Add(l.Duration)
+		nextStart = FindNext(startSchedules, nextStart)
+	}
+
+	return &stop
+}
+
 // NextStartTime returns the next start time.
 //   - If it should be start now than it returns nil.
 func (l *ScheduleGroup) NextStartTime(now time.Time) *time.Time {
@@ -56,3 +56,77 @@ func TestLoop_NextStartTime(t *testing.T) {
 		})
 	}
 }
+
+func TestScheduleDuration_NextStartTime(t *testing.T) {
+	logAMS, err := time.LoadLocation("Europe/Amsterdam")
+	if err != nil {
+		t.Fatalf("failed to load location: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		startSpec []string
+		duration  time.Duration
+		now       time.Time
+		want      *time.Time
+	}{
+		{
+			name:      "before start",
+			startSpec: []string{"0 2 * * *"},
+			duration:  90 * time.Minute,
+			now:       time.Date(2024, 1, 2, 1, 0, 0, 0, time.UTC),
+			want: func() *time.Time {
+				t := time.Date(2024, 1, 2, 2, 0, 0, 0, time.UTC)
+				return &t
+			}(),
+		},
+		{
+			name:      "in window",
+			startSpec: []string{"0 2 * * *"},
+			duration:  90 * time.Minute,
+			now:       time.Date(2024, 1, 2, 3, 29, 0, 0, time.UTC),
+			want:      nil,
+		},
+		{
+			name:      "after window",
+			startSpec: []string{"0 2 * * *"},
+			duration:  90 * time.Minute,
+			now:       time.Date(2024, 1, 2, 3, 31, 0, 0, time.UTC),
+			want: func() *time.Time {
+				t := time.Date(2024, 1, 3, 2, 0, 0, 0, time.UTC)
+				return &t
+			}(),
+		},
+		{
+			name:      "overlapping windows",
+			startSpec: []string{"0 * * * *"},
+			duration:  90 * time.Minute,
+			now:       time.Date(2024, 1, 2, 3, 59, 0, 0, time.UTC),
+			want:      nil,
+		},
+		{
+			name:      "in window across DST change",
+			startSpec: []string{"CRON_TZ=Europe/Amsterdam 30 1 * * *"},
+			duration:  90 * time.Minute,
+			// 01:30 + 90m is 04:00 in summer time
+			now:  time.Date(2024, 3, 31, 3, 59, 0, 0, logAMS),
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := hardloop.NewScheduleDuration(tt.startSpec, tt.duration)
+			if err != nil {
+				t.Fatalf("could not construct receiver type: %v", err)
+			}
+			got := l.NextStartTime(tt.now)
+			if (got == nil) != (tt.want == nil) || (got != nil && !got.Equal(*tt.want)) {
+				t.Errorf("NextStartTime() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := hardloop.NewScheduleDuration([]string{"0 2 * * *"}, 0); err == nil {
+		t.Errorf("expected error for zero duration")
+	}
+}
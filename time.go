@@ -1,6 +1,9 @@
 package hardloop
 
-import "time"
+import (
+	"iter"
+	"time"
+)
 
 func FindPrev(schedules []Schedule, now time.Time) time.Time {
 	prevTime := time.Time{}
@@ -46,3 +49,48 @@ func FindNext(schedules []Schedule, now time.Time) time.Time {
 
 	return nextTime
 }
+
+// Between returns the activations of the schedules after from until to, to is included.
+//   - Use it to find the missed runs since the last run.
+//   - limit is the maximum number of activations, zero or negative means no limit.
+func Between(schedules []Schedule, from, to time.Time, limit int) []time.Time {
+	var times []time.Time
+
+	for t := range IterNext(schedules, from, limit) {
+		if t.After(to) {
+			break
+		}
+
+		times = append(times, t)
+	}
+
+	return times
+}
+
+// IterNext returns an iterator of the next activations of the schedules after from.
+//   - limit is the maximum number of activations, zero or negative means no limit.
+func IterNext(schedules []Schedule, from time.Time, limit int) iter.Seq[time.Time] {
+	return func(yield func(time.Time) bool) {
+		t := from
+		for i := 0; limit <= 0 || i < limit; i++ {
+			t = FindNext(schedules, t)
+			if t.IsZero() || !yield(t) {
+				return
+			}
+		}
+	}
+}
+
+// IterPrev returns an iterator of the previous activations of the schedules before from.
+//   - limit is the maximum number of activations, zero or negative means no limit.
+func IterPrev(schedules []Schedule, from time.Time, limit int) iter.Seq[time.Time] {
+	return func(yield func(time.Time) bool) {
+		t := from
+		for i := 0; limit <= 0 || i < limit; i++ {
+			t = FindPrev(schedules, t)
+			if t.IsZero() || !yield(t) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package hardloop_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/worldline-go/hardloop"
+)
+
+func TestBetween(t *testing.T) {
+	// every 6 hours
+	schedules := []hardloop.Schedule{mustParse(t, "0 */6 * * *")}
+
+	day := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		from  time.Time
+		to    time.Time
+		limit int
+		want  []time.Time
+	}{
+		{
+			name: "to is included",
+			from: day,
+			to:   day.Add(12 * time.Hour),
+			want: []time.Time{day.Add(6 * time.Hour), day.Add(12 * time.Hour)},
+		},
+		{
+			name: "no activation in range",
+			from: day.Add(time.Hour),
+			to:   day.Add(5 * time.Hour),
+		},
+		{
+			name: "to before from",
+			from: day.Add(12 * time.Hour),
+			to:   day,
+		},
+		{
+			name: "from equal to",
+			from: day.Add(6 * time.Hour),
+			to:   day.Add(6 * time.Hour),
+		},
+		{
+			name:  "limit",
+			from:  day,
+			to:    day.AddDate(1, 0, 0),
+			limit: 3,
+			want:  []time.Time{day.Add(6 * time.Hour), day.Add(12 * time.Hour), day.Add(18 * time.Hour)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hardloop.Between(schedules, tt.from, tt.to, tt.limit); !slices.Equal(got, tt.want) {
+				t.Errorf("Between() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
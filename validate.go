@@ -0,0 +1,196 @@
+package hardloop
+
+import (
+	"fmt"
+	"time"
+)
+
+// validateCheckLimit is the number of activations checked for each spec in validation.
+const validateCheckLimit = 100
+
+// WarningKind is the type of a validation warning.
+type WarningKind string
+
+const (
+	// WarningNeverFires is a spec without any activation in YearLimit.
+	WarningNeverFires WarningKind = "never_fires"
+	// WarningStartWithoutStop is a start spec without a following stop.
+	WarningStartWithoutStop WarningKind = "start_without_stop"
+	// WarningStopWithoutStart is a stop spec never closing a started window.
+	WarningStopWithoutStart WarningKind = "stop_without_start"
+	// WarningShortWindow is a window not longer than the gap duration of the loop.
+	WarningShortWindow WarningKind = "short_window"
+	// WarningDSTGap is a spec activating in a time skipped by daylight saving.
+	WarningDSTGap WarningKind = "dst_gap"
+)
+
+// Warning is a possible problem of a spec.
+type Warning struct {
+	Kind WarningKind
+	Spec string
+	// Time is the related activation time, zero if not exist.
+	Time    time.Time
+	Message string
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("%s [%s]: %s", w.Kind, w.Spec, w.Message)
+}
+
+// Validate checks the start and stop specs and returns the warnings.
+//   - Options of the loop are used like in NewLoop, e.g. WithGapDurationStart and WithParser.
+//   - Returns error if a spec cannot be parsed.
+func Validate(startSpecs, stopSpecs []string, opts ...LoopOption) ([]Warning, error) {
+	l := newLoop(nil, opts)
+
+	return validate(l.parser, startSpecs, stopSpecs, l.gapDurationStart, time.Now())
+}
+
+func validate(parser Parser, startSpecs, stopSpecs []string, gap time.Duration, now time.Time) ([]Warning, error) {
+	startSchedules, err := parseSpecs(parser, startSpecs)
+	if err != nil {
+		return nil, err
+	}
+
+	stopSchedules, err := parseSpecs(parser, stopSpecs)
+	if err != nil {
+		return nil, err
+	}
+
+	var warnings []Warning
+
+	for i, spec := range startSpecs {
+		warnings = append(warnings, validateSpec(spec, startSchedules[i], now)...)
+		if len(stopSchedules) > 0 {
+			warnings = append(warnings, validateStart(spec, startSchedules[i], stopSchedules, gap, now)...)
+		}
+	}
+
+	for i, spec := range stopSpecs {
+		warnings = append(warnings, validateSpec(spec, stopSchedules[i], now)...)
+		if len(startSchedules) > 0 {
+			warnings = append(warnings, validateStop(spec, stopSchedules[i], startSchedules, stopSchedules, now)...)
+		}
+	}
+
+	return warnings, nil
+}
+
+func parseSpecs(parser Parser, specs []string) ([]Schedule, error) {
+	schedules := make([]Schedule, 0, len(specs))
+
+	for _, spec := range specs {
+		schedule, err := parser.parse(spec)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %q: %w", spec, err)
+		}
+
+		schedules = append(schedules, schedule)
+	}
+
+	return schedules, nil
+}
+
+// validateSpec checks the activations of the spec itself.
+func validateSpec(spec string, schedule Schedule, now time.Time) []Warning {
+	if schedule.Next(now).IsZero() {
+		return []Warning{{
+			Kind:    WarningNeverFires,
+			Spec:    spec,
+			Message: fmt.Sprintf("no activation in %d years", horizon(schedule)),
+		}}
+	}
+
+	s, ok := schedule.(*SpecSchedule)
+	if !ok || s.SpecSchedule == nil {
+		return nil
+	}
+
+	var warnings []Warning
+
+	for _, gap := range dstGaps(s.location(), now, now.AddDate(s.yearLimit(), 0, 0)) {
+		for wall := gap.wall; wall.Before(gap.wall.Add(gap.length)); wall = wall.Add(time.Second) {
+			if !s.matchesWall(wall) {
+				continue
+			}
+
+			warnings = append(warnings, Warning{
+				Kind:    WarningDSTGap,
+				Spec:    spec,
+				Time:    gap.at,
+				Message: fmt.Sprintf("%s doesn't exist in %s", wall.Format(time.DateTime), s.location()),
+			})
+
+			break
+		}
+	}
+
+	return warnings
+}
+
+// validateStart checks that the start spec is followed by a stop longer than the gap duration.
+func validateStart(spec string, schedule Schedule, stopSchedules []Schedule, gap time.Duration, now time.Time) []Warning {
+	var warnings []Warning
+
+	shortWindow := false
+	start := now
+
+	for range validateCheckLimit {
+		if start = schedule.Next(start); start.IsZero() {
+			break
+		}
+
+		stop := FindNext(stopSchedules, start)
+		if stop.IsZero() {
+			return append(warnings, Warning{
+				Kind:    WarningStartWithoutStop,
+				Spec:    spec,
+				Time:    start,
+				Message: fmt.Sprintf("no stop after start at %s", start),
+			})
+		}
+
+		if !shortWindow && stop.Sub(start) <= gap {
+			shortWindow = true
+			warnings = append(warnings, Warning{
+				Kind:    WarningShortWindow,
+				Spec:    spec,
+				Time:    start,
+				Message: fmt.Sprintf("window [%s, %s] is not longer than the gap duration %s", start, stop, gap),
+			})
+		}
+	}
+
+	return warnings
+}
+
+// validateStop checks that the stop spec closes a started window.
+func validateStop(spec string, schedule Schedule, startSchedules, stopSchedules []Schedule, now time.Time) []Warning {
+	stop := now
+
+	for i := range validateCheckLimit {
+		if stop = schedule.Next(stop); stop.IsZero() {
+			if i == 0 {
+				// reported as never fires
+				return nil
+			}
+
+			break
+		}
+
+		prevStart := FindPrev(startSchedules, stop)
+		if prevStart.IsZero() {
+			continue
+		}
+
+		if prevStop := FindPrev(stopSchedules, stop); prevStop.IsZero() || !prevStop.After(prevStart) {
+			return nil
+		}
+	}
+
+	return []Warning{{
+		Kind:    WarningStopWithoutStart,
+		Spec:    spec,
+		Message: "stop never comes after a start",
+	}}
+}
@@ -0,0 +1,90 @@
+package hardloop
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		startSpecs []string
+		stopSpecs  []string
+		gapStart   time.Duration
+		want       []WarningKind
+	}{
+		{
+			name:       "valid",
+			startSpecs: []string{"0 7 * * 1-5", "0 9 * * 6"},
+			stopSpecs:  []string{"0 17 * * 1-5", "0 13 * * 6"},
+		},
+		{
+			name:       "never fires",
+			startSpecs: []string{"0 7 30 2 *"},
+			stopSpecs:  []string{"0 17 * * *"},
+			want:       []WarningKind{WarningNeverFires, WarningStopWithoutStart},
+		},
+		{
+			name:       "start without stop",
+			startSpecs: []string{"0 7 * * *"},
+			// next monday on 29 february is in 2044
+			stopSpecs: []string{"0 17 29 2 1"},
+			want:      []WarningKind{WarningStartWithoutStop, WarningNeverFires},
+		},
+		{
+			name:       "stop without start",
+			startSpecs: []string{"0 7 * * *"},
+			stopSpecs:  []string{"0 17 * * *", "0 18 * * *"},
+			want:       []WarningKind{WarningStopWithoutStart},
+		},
+		{
+			name:       "short window",
+			startSpecs: []string{"0 7 * * *"},
+			stopSpecs:  []string{"1 7 * * *"},
+			gapStart:   time.Minute,
+			want:       []WarningKind{WarningShortWindow},
+		},
+		{
+			name:       "dst gap",
+			startSpecs: []string{"CRON_TZ=Europe/Amsterdam 30 2 26 3 *"},
+			stopSpecs:  []string{"CRON_TZ=Europe/Amsterdam 30 4 26 3 *"},
+			want:       []WarningKind{WarningDSTGap},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gap := GapDurationStart
+			if tt.gapStart != 0 {
+				gap = tt.gapStart
+			}
+
+			warnings, err := validate(Parser{}, tt.startSpecs, tt.stopSpecs, gap, now)
+			if err != nil {
+				t.Fatalf("validate() error = %v", err)
+			}
+
+			if len(warnings) != len(tt.want) {
+				t.Fatalf("validate() got %v, want %v", warnings, tt.want)
+			}
+
+			for i, w := range warnings {
+				if w.Kind != tt.want[i] {
+					t.Errorf("validate() got %v, want %v", w, tt.want[i])
+				}
+			}
+		})
+	}
+
+	// gap duration of the loop options
+	warnings, err := Validate([]string{"0 7 * * *"}, []string{"1 7 * * *"}, WithGapDurationStart(time.Minute))
+	if err != nil || len(warnings) != 1 || warnings[0].Kind != WarningShortWindow {
+		t.Errorf("Validate() = %v, %v, want %v", warnings, err, WarningShortWindow)
+	}
+
+	if _, err := Validate([]string{"* * *"}, nil); err == nil {
+		t.Errorf("expected error for invalid spec")
+	}
+}
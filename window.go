@@ -0,0 +1,264 @@
+package hardloop
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// windowCheckLimit is the maximum number of activations checked for each window in validation.
+const windowCheckLimit = 10000
+
+var (
+	// ErrWindowNeverCloses is returned when the stop of a window never comes after its start.
+	ErrWindowNeverCloses = errors.New("window never closes")
+	// ErrWindowOverlap is returned when windows are running at the same time.
+	ErrWindowOverlap = errors.New("windows overlap")
+)
+
+// Window pairs a start spec with the stop spec closing it.
+type Window struct {
+	Start string
+	Stop  string
+}
+
+// WindowSchedule pairs a start schedule with the stop schedule closing it.
+type WindowSchedule struct {
+	Start Schedule
+	Stop  Schedule
+}
+
+// NewWindowSchedule returns a schedule group with paired start and stop specs.
+//   - Returns ErrWindowNeverCloses or ErrWindowOverlap if windows are not valid from now, see ValidateWindows.
+func NewWindowSchedule(windows []Window) (*ScheduleGroup, error) {
+	return newWindowSchedule(Parser{}, windows)
+}
+
+func newWindowSchedule(parser Parser, windows []Window) (*ScheduleGroup, error) {
+	windowSchedules := make([]WindowSchedule, 0, len(windows))
+
+	for _, window := range windows {
+		start, err := parser.parse(window.Start)
+		if err != nil {
+			return nil, err
+		}
+
+		stop, err := parser.parse(window.Stop)
+		if err != nil {
+			return nil, err
+		}
+
+		windowSchedules = append(windowSchedules, WindowSchedule{Start: start, Stop: stop})
+	}
+
+	scheduleGroup := &ScheduleGroup{
+		WindowSchedules: windowSchedules,
+	}
+
+	if err := scheduleGroup.ValidateWindows(time.Now()); err != nil {
+		return nil, err
+	}
+
+	return scheduleGroup, nil
+}
+
+// ValidateWindows checks the activations of the windows starting in YearLimit years after the given time.
+//   - At most 10000 activations are checked for each window.
+//   - Returns ErrWindowNeverCloses if a stop doesn't come after its start.
+//   - Returns ErrWindowOverlap if two windows are running at the same time.
+func (l *ScheduleGroup) ValidateWindows(now time.Time) error {
+	type interval struct {
+		window      int
+		start, stop time.Time
+	}
+
+	var intervals []interval
+
+	for i, window := range l.WindowSchedules {
+		until := now.AddDate(horizon(window.Start, window.Stop), 0, 0)
+
+		t := now
+		for range windowCheckLimit {
+			start := window.Start.Next(t)
+			if start.IsZero() || !start.Before(until) {
+				break
+			}
+
+			stop := window.Stop.Next(start)
+			if stop.IsZero() {
+				return fmt.Errorf("%w: window %d starts at %s", ErrWindowNeverCloses, i, start)
+			}
+
+			intervals = append(intervals, interval{window: i, start: start, stop: stop})
+			t = stop
+		}
+	}
+
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i].start.Before(intervals[j].start)
+	})
+
+	for i := 1; i < len(intervals); i++ {
+		prev, cur := intervals[i-1], intervals[i]
+		if cur.start.Before(prev.stop) {
+			return fmt.Errorf("%w: window %d [%s, %s] and window %d [%s, %s]",
+				ErrWindowOverlap, prev.window, prev.start, prev.stop, cur.window, cur.start, cur.stop)
+		}
+	}
+
+	return nil
+}
+
+// getWindowStopTime returns the latest stop time of the running windows, nil if not in a window.
+func (l *ScheduleGroup) getWindowStopTime(now time.Time) *time.Time {
+	var stopTime *time.Time
+
+	for _, window := range l.runningWindows(now) {
+		if stopTime == nil || window.Stop.After(*stopTime) {
+			stopTime = &window.Stop
+		}
+	}
+
+	return stopTime
+}
+
+// runningWindows returns the paired windows running at now.
+func (l *ScheduleGroup) runningWindows(now time.Time) []Interval {
+	var intervals []Interval
+
+	for _, window := range l.WindowSchedules {
+		prevStart := Exclude(window.Start, l.Exclusions...).Prev(now)
+		if prevStart.IsZero() {
+			continue
+		}
+
+		// if prevStop is after prevStart, then the window is closed
+		if prevStop := window.Stop.Prev(now); !prevStop.IsZero() && prevStop.After(prevStart) {
+			continue
+		}
+
+		nextStop := window.Stop.Next(now)
+		if nextStop.IsZero() {
+			continue
+		}
+
+		intervals = append(intervals, Interval{Start: prevStart, Stop: nextStop})
+	}
+
+	return intervals
+}
+
+// Interval is a time range the loop is active in, Stop is excluded.
+type Interval struct {
+	Start time.Time
+	// Stop is zero if the interval never ends.
+	Stop time.Time
+}
+
+// ActiveAt returns true if the loop should be running at the given time.
+func (l *ScheduleGroup) ActiveAt(t time.Time) bool {
+	_, ok := l.CurrentWindow(t)
+
+	return ok
+}
+
+// CurrentWindow returns the window containing the given time.
+//   - Without stop schedules the window never ends and starts in the last start time.
+//   - Without any schedule the window is always active with zero start and stop.
+func (l *ScheduleGroup) CurrentWindow(t time.Time) (Interval, bool) {
+	// include the activations at t
+	t = t.Add(time.Nanosecond)
+
+	if stop, _ := l.getStopTime(t); stop != nil {
+		return Interval{Start: l.windowStart(t), Stop: *stop}, true
+	}
+
+	if len(l.WindowSchedules) > 0 || l.Duration > 0 || len(l.StopSchedules) > 0 {
+		return Interval{}, false
+	}
+
+	startSchedules := l.startSchedules()
+	if len(startSchedules) == 0 {
+		return Interval{}, true
+	}
+
+	prevStart := FindPrev(startSchedules, t)
+	if prevStart.IsZero() {
+		return Interval{}, false
+	}
+
+	return Interval{Start: prevStart}, true
+}
+
+// Windows returns the windows the loop is active in between from and to.
+//   - First window can start before from.
+func (l *ScheduleGroup) Windows(from, to time.Time) []Interval {
+	var intervals []Interval
+
+	for t := from; t.Before(to); {
+		if window, ok := l.CurrentWindow(t); ok {
+			intervals = append(intervals, window)
+			if window.Stop.IsZero() {
+				break
+			}
+
+			t = window.Stop
+
+			continue
+		}
+
+		nextStart, _ := l.getStartTime(t)
+		if nextStart == nil {
+			break
+		}
+
+		t = *nextStart
+	}
+
+	return intervals
+}
+
+// windowStart returns the start time of the window running at t.
+func (l *ScheduleGroup) windowStart(t time.Time) time.Time {
+	startSchedules := l.startSchedules()
+
+	switch {
+	case len(l.WindowSchedules) > 0:
+		var start time.Time
+		for _, window := range l.runningWindows(t) {
+			if start.IsZero() || window.Start.Before(start) {
+				start = window.Start
+			}
+		}
+
+		return start
+	case l.Duration > 0:
+		// go back in the overlapping windows
+		start := FindPrev(startSchedules, t)
+		for i := 0; i < windowMergeLimit; i++ {
+			prev := FindPrev(startSchedules, start)
+			if prev.IsZero() || prev.Add(l.Duration).Before(start) {
+				break
+			}
+
+			start = prev
+		}
+
+		return start
+	default:
+		prevStop := FindPrev(l.StopSchedules, t)
+		prevStart := FindPrev(startSchedules, t)
+
+		switch {
+		case prevStart.IsZero():
+			// restarted in the stop time
+			return prevStop
+		case prevStop.IsZero():
+			return prevStart
+		default:
+			// first start after the stop
+			return FindNext(startSchedules, prevStop.Add(-time.Nanosecond))
+		}
+	}
+}
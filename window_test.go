@@ -0,0 +1,258 @@
+package hardloop_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/worldline-go/hardloop"
+)
+
+func TestNewWindowSchedule(t *testing.T) {
+	tests := []struct {
+		name    string
+		windows []hardloop.Window
+		wantErr error
+	}{
+		{
+			name: "valid",
+			windows: []hardloop.Window{
+				{Start: "0 7 * * 1-5", Stop: "0 17 * * 1-5"},
+				{Start: "0 9 * * 6", Stop: "0 13 * * 6"},
+			},
+		},
+		{
+			name: "never closes",
+			windows: []hardloop.Window{
+				{Start: "0 7 * * *", Stop: "0 17 30 2 *"},
+			},
+			wantErr: hardloop.ErrWindowNeverCloses,
+		},
+		{
+			name: "overlap",
+			windows: []hardloop.Window{
+				{Start: "0 7 * * 1-5", Stop: "0 17 * * 1-5"},
+				{Start: "0 12 * * 1", Stop: "0 20 * * 1"},
+			},
+			wantErr: hardloop.ErrWindowOverlap,
+		},
+		{
+			name: "overlap once a year",
+			windows: []hardloop.Window{
+				{Start: "0 7 1 1 *", Stop: "0 17 1 1 *"},
+				{Start: "0 12 * * *", Stop: "0 13 * * *"},
+			},
+			wantErr: hardloop.ErrWindowOverlap,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := hardloop.NewWindowSchedule(tt.windows)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("NewWindowSchedule() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScheduleGroup_ValidateWindows(t *testing.T) {
+	scheduleGroup := &hardloop.ScheduleGroup{}
+	for _, window := range []hardloop.Window{
+		{Start: "0 7 1 1 *", Stop: "0 17 1 1 *"},
+		{Start: "0 12 * * *", Stop: "0 13 * * *"},
+	} {
+		start, _ := hardloop.ParseStandard(window.Start)
+		stop, _ := hardloop.ParseStandard(window.Stop)
+		scheduleGroup.WindowSchedules = append(scheduleGroup.WindowSchedules, hardloop.WindowSchedule{Start: start, Stop: stop})
+	}
+
+	// result should not depend on the date of the check
+	for _, now := range []time.Time{
+		time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		if err := scheduleGroup.ValidateWindows(now); !errors.Is(err, hardloop.ErrWindowOverlap) {
+			t.Errorf("ValidateWindows(%s) error = %v, want %v", now, err, hardloop.ErrWindowOverlap)
+		}
+	}
+}
+
+func TestWindowSchedule_NextStartTime(t *testing.T) {
+	l, err := hardloop.NewWindowSchedule([]hardloop.Window{
+		{Start: "0 7 * * 1-5", Stop: "0 17 * * 1-5"},
+		{Start: "0 9 * * 6", Stop: "0 13 * * 6"},
+	})
+	if err != nil {
+		t.Fatalf("could not construct receiver type: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want *time.Time
+	}{
+		{
+			name: "in weekday window",
+			now:  time.Date(2024, 1, 5, 10, 0, 0, 0, time.UTC),
+			want: nil,
+		},
+		{
+			name: "after weekday window",
+			now:  time.Date(2024, 1, 5, 18, 0, 0, 0, time.UTC),
+			want: func() *time.Time {
+				t := time.Date(2024, 1, 6, 9, 0, 0, 0, time.UTC)
+				return &t
+			}(),
+		},
+		{
+			name: "in saturday window",
+			now:  time.Date(2024, 1, 6, 12, 0, 0, 0, time.UTC),
+			want: nil,
+		},
+		{
+			name: "after saturday window",
+			now:  time.Date(2024, 1, 6, 14, 0, 0, 0, time.UTC),
+			want: func() *time.Time {
+				t := time.Date(2024, 1, 8, 7, 0, 0, 0, time.UTC)
+				return &t
+			}(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := l.NextStartTime(tt.now)
+			if (got == nil) != (tt.want == nil) || (got != nil && !got.Equal(*tt.want)) {
+				t.Errorf("NextStartTime() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleGroup_Windows(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule func() (*hardloop.ScheduleGroup, error)
+		from     time.Time
+		to       time.Time
+		want     []hardloop.Interval
+	}{
+		{
+			name: "start and stop",
+			schedule: func() (*hardloop.ScheduleGroup, error) {
+				return hardloop.NewSchedule([]string{"0 7 * * 1-5", "0 9 * * 6"}, []string{"0 17 * * 1-5", "0 13 * * 6"})
+			},
+			// friday 10:00 to monday 10:00
+			from: time.Date(2024, 1, 5, 10, 0, 0, 0, time.UTC),
+			to:   time.Date(2024, 1, 8, 10, 0, 0, 0, time.UTC),
+			want: []hardloop.Interval{
+				{Start: time.Date(2024, 1, 5, 7, 0, 0, 0, time.UTC), Stop: time.Date(2024, 1, 5, 17, 0, 0, 0, time.UTC)},
+				{Start: time.Date(2024, 1, 6, 9, 0, 0, 0, time.UTC), Stop: time.Date(2024, 1, 6, 13, 0, 0, 0, time.UTC)},
+				{Start: time.Date(2024, 1, 8, 7, 0, 0, 0, time.UTC), Stop: time.Date(2024, 1, 8, 17, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name: "restarts in the window",
+			schedule: func() (*hardloop.ScheduleGroup, error) {
+				return hardloop.NewSchedule([]string{"*/30 7-16 * * *"}, []string{"0 17 * * *"})
+			},
+			from: time.Date(2024, 1, 5, 10, 0, 0, 0, time.UTC),
+			to:   time.Date(2024, 1, 5, 20, 0, 0, 0, time.UTC),
+			want: []hardloop.Interval{
+				{Start: time.Date(2024, 1, 5, 7, 0, 0, 0, time.UTC), Stop: time.Date(2024, 1, 5, 17, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name: "overlapping durations",
+			schedule: func() (*hardloop.ScheduleGroup, error) {
+				return hardloop.NewScheduleDuration([]string{"0 2,3 * * *"}, 90*time.Minute)
+			},
+			from: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			to:   time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			want: []hardloop.Interval{
+				{Start: time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC), Stop: time.Date(2024, 1, 1, 4, 30, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name: "paired windows",
+			schedule: func() (*hardloop.ScheduleGroup, error) {
+				return hardloop.NewWindowSchedule([]hardloop.Window{
+					{Start: "0 22 * * 5", Stop: "0 6 * * 1"},
+				})
+			},
+			from: time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC),
+			to:   time.Date(2024, 1, 13, 0, 0, 0, 0, time.UTC),
+			want: []hardloop.Interval{
+				{Start: time.Date(2024, 1, 5, 22, 0, 0, 0, time.UTC), Stop: time.Date(2024, 1, 8, 6, 0, 0, 0, time.UTC)},
+				{Start: time.Date(2024, 1, 12, 22, 0, 0, 0, time.UTC), Stop: time.Date(2024, 1, 15, 6, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name: "without stop",
+			schedule: func() (*hardloop.ScheduleGroup, error) {
+				return hardloop.NewSchedule([]string{"0 7 * * *"}, nil)
+			},
+			from: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			to:   time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+			want: []hardloop.Interval{
+				// started in the previous day and never stops
+				{Start: time.Date(2023, 12, 31, 7, 0, 0, 0, time.UTC)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := tt.schedule()
+			if err != nil {
+				t.Fatalf("could not construct receiver type: %v", err)
+			}
+
+			got := l.Windows(tt.from, tt.to)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Windows() got = %v, want %v", got, tt.want)
+			}
+
+			for i := range got {
+				if !got[i].Start.Equal(tt.want[i].Start) || !got[i].Stop.Equal(tt.want[i].Stop) {
+					t.Errorf("Windows()[%d] got = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestScheduleGroup_CurrentWindow(t *testing.T) {
+	l, err := hardloop.NewSchedule([]string{"0 7 * * *"}, []string{"0 17 * * *"})
+	if err != nil {
+		t.Fatalf("could not construct receiver type: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		now    time.Time
+		active bool
+	}{
+		{name: "before start", now: time.Date(2024, 1, 1, 6, 59, 59, 0, time.UTC), active: false},
+		{name: "at start", now: time.Date(2024, 1, 1, 7, 0, 0, 0, time.UTC), active: true},
+		{name: "in window", now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), active: true},
+		{name: "at stop", now: time.Date(2024, 1, 1, 17, 0, 0, 0, time.UTC), active: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := l.ActiveAt(tt.now); got != tt.active {
+				t.Errorf("ActiveAt() got = %v, want %v", got, tt.active)
+			}
+
+			window, ok := l.CurrentWindow(tt.now)
+			if ok != tt.active {
+				t.Fatalf("CurrentWindow() got = %v, want %v", ok, tt.active)
+			}
+
+			if ok && (!window.Start.Equal(time.Date(2024, 1, 1, 7, 0, 0, 0, time.UTC)) ||
+				!window.Stop.Equal(time.Date(2024, 1, 1, 17, 0, 0, 0, time.UTC))) {
+				t.Errorf("CurrentWindow() got = %v", window)
+			}
+		})
+	}
+}